@@ -1,13 +1,17 @@
 package kk
 
 import (
+	"cmp"
+	"context"
 	"fmt"
 )
 
 // Count returns the number of items in the query.
 func Count[T any](q *KKQuery[T]) int {
 	count := 0
-	iter := q.iterate()
+	r := newRun(context.Background())
+	defer r.close()
+	iter := q.iterate(r)
 	for {
 		_, ok := iter()
 		if !ok {
@@ -18,10 +22,35 @@ func Count[T any](q *KKQuery[T]) int {
 	return count
 }
 
+// CountErr returns the number of items in the query and the error reported
+// by the query chain, if any.
+func CountErr[T any](q *KKQuery[T]) (int, error) {
+	return CountCtx(context.Background(), q)
+}
+
+// CountCtx returns the number of items in the query, stopping with ctx.Err()
+// if ctx is cancelled.
+func CountCtx[T any](ctx context.Context, q *KKQuery[T]) (int, error) {
+	r := newRun(ctx)
+	defer r.close()
+	count := 0
+	iter := q.iterate(r)
+	for !r.done() {
+		_, ok := iter()
+		if !ok {
+			break
+		}
+		count++
+	}
+	return count, r.err()
+}
+
 // Sum returns the sum of values produced by the selector function.
 func Sum[T any, N Number](q *KKQuery[T], selector func(T) N) N {
 	var sum N
-	iter := q.iterate()
+	r := newRun(context.Background())
+	defer r.close()
+	iter := q.iterate(r)
 	for {
 		item, ok := iter()
 		if !ok {
@@ -39,16 +68,260 @@ type Number interface {
 		~float32 | ~float64
 }
 
+// Min returns the smallest item, or the zero value if the query is empty.
+// The second return value indicates whether the query had any items.
+func Min[T cmp.Ordered](q *KKQuery[T]) (T, bool) {
+	least, ok, _ := MinCtx(context.Background(), q)
+	return least, ok
+}
+
+// MinErr is like Min and also returns the error reported by the query chain, if any.
+func MinErr[T cmp.Ordered](q *KKQuery[T]) (T, bool, error) {
+	return MinCtx(context.Background(), q)
+}
+
+// MinCtx is like MinErr, stopping with ctx.Err() if ctx is cancelled.
+func MinCtx[T cmp.Ordered](ctx context.Context, q *KKQuery[T]) (T, bool, error) {
+	return ReduceCtx(
+		ctx, q, func(least T, item T) T {
+			if item < least {
+				return item
+			}
+			return least
+		},
+	)
+}
+
+// Max returns the largest item, or the zero value if the query is empty.
+// The second return value indicates whether the query had any items.
+func Max[T cmp.Ordered](q *KKQuery[T]) (T, bool) {
+	greatest, ok, _ := MaxCtx(context.Background(), q)
+	return greatest, ok
+}
+
+// MaxErr is like Max and also returns the error reported by the query chain, if any.
+func MaxErr[T cmp.Ordered](q *KKQuery[T]) (T, bool, error) {
+	return MaxCtx(context.Background(), q)
+}
+
+// MaxCtx is like MaxErr, stopping with ctx.Err() if ctx is cancelled.
+func MaxCtx[T cmp.Ordered](ctx context.Context, q *KKQuery[T]) (T, bool, error) {
+	return ReduceCtx(
+		ctx, q, func(greatest T, item T) T {
+			if item > greatest {
+				return item
+			}
+			return greatest
+		},
+	)
+}
+
+// MinBy returns the item with the smallest key. Of items with equal keys the
+// first is returned. The second return value indicates whether the query had any items.
+func MinBy[T any, K cmp.Ordered](q *KKQuery[T], keyFn func(T) K) (T, bool) {
+	least, ok, _ := MinByCtx(context.Background(), q, keyFn)
+	return least, ok
+}
+
+// MinByErr is like MinBy and also returns the error reported by the query chain, if any.
+func MinByErr[T any, K cmp.Ordered](q *KKQuery[T], keyFn func(T) K) (T, bool, error) {
+	return MinByCtx(context.Background(), q, keyFn)
+}
+
+// MinByCtx is like MinByErr, stopping with ctx.Err() if ctx is cancelled.
+func MinByCtx[T any, K cmp.Ordered](ctx context.Context, q *KKQuery[T], keyFn func(T) K) (T, bool, error) {
+	return extremeBy(ctx, q, keyFn, func(key K, best K) bool { return key < best })
+}
+
+// MaxBy returns the item with the largest key. Of items with equal keys the
+// first is returned. The second return value indicates whether the query had any items.
+func MaxBy[T any, K cmp.Ordered](q *KKQuery[T], keyFn func(T) K) (T, bool) {
+	greatest, ok, _ := MaxByCtx(context.Background(), q, keyFn)
+	return greatest, ok
+}
+
+// MaxByErr is like MaxBy and also returns the error reported by the query chain, if any.
+func MaxByErr[T any, K cmp.Ordered](q *KKQuery[T], keyFn func(T) K) (T, bool, error) {
+	return MaxByCtx(context.Background(), q, keyFn)
+}
+
+// MaxByCtx is like MaxByErr, stopping with ctx.Err() if ctx is cancelled.
+func MaxByCtx[T any, K cmp.Ordered](ctx context.Context, q *KKQuery[T], keyFn func(T) K) (T, bool, error) {
+	return extremeBy(ctx, q, keyFn, func(key K, best K) bool { return key > best })
+}
+
+// extremeBy returns the first item whose key is better than every other key.
+func extremeBy[T any, K cmp.Ordered](
+	ctx context.Context, q *KKQuery[T], keyFn func(T) K, better func(key K, best K) bool,
+) (T, bool, error) {
+	r := newRun(ctx)
+	defer r.close()
+	iter := q.iterate(r)
+
+	var best T
+	var bestKey K
+	found := false
+	for !r.done() {
+		item, ok := iter()
+		if !ok {
+			break
+		}
+		if key := keyFn(item); !found || better(key, bestKey) {
+			best, bestKey, found = item, key, true
+		}
+	}
+	return best, found, r.err()
+}
+
+// Average returns the mean of values produced by the selector function.
+// For an empty query it returns 0 and false.
+func Average[T any, N Number](q *KKQuery[T], selector func(T) N) (float64, bool) {
+	mean, ok, _ := AverageCtx(context.Background(), q, selector)
+	return mean, ok
+}
+
+// AverageErr is like Average and also returns the error reported by the query chain, if any.
+func AverageErr[T any, N Number](q *KKQuery[T], selector func(T) N) (float64, bool, error) {
+	return AverageCtx(context.Background(), q, selector)
+}
+
+// AverageCtx is like AverageErr, stopping with ctx.Err() if ctx is cancelled.
+func AverageCtx[T any, N Number](ctx context.Context, q *KKQuery[T], selector func(T) N) (float64, bool, error) {
+	stats, err := SummarizeCtx(ctx, q, selector)
+	return stats.Mean, stats.Count > 0, err
+}
+
+// Reduce combines the items from first to last with fn, starting with the
+// first item. For an empty query it returns the zero value and false.
+func Reduce[T any](q *KKQuery[T], fn func(acc T, item T) T) (T, bool) {
+	acc, ok, _ := ReduceCtx(context.Background(), q, fn)
+	return acc, ok
+}
+
+// ReduceErr is like Reduce and also returns the error reported by the query chain, if any.
+func ReduceErr[T any](q *KKQuery[T], fn func(acc T, item T) T) (T, bool, error) {
+	return ReduceCtx(context.Background(), q, fn)
+}
+
+// ReduceCtx is like ReduceErr, stopping with ctx.Err() if ctx is cancelled.
+func ReduceCtx[T any](ctx context.Context, q *KKQuery[T], fn func(acc T, item T) T) (T, bool, error) {
+	r := newRun(ctx)
+	defer r.close()
+	iter := q.iterate(r)
+
+	var acc T
+	found := false
+	for !r.done() {
+		item, ok := iter()
+		if !ok {
+			break
+		}
+		if found {
+			acc = fn(acc, item)
+		} else {
+			acc, found = item, true
+		}
+	}
+	return acc, found, r.err()
+}
+
+// Fold combines the items from first to last with fn, starting with seed.
+// For an empty query it returns seed.
+// This is a function (not a method) because the accumulator can have a different type.
+func Fold[T any, A any](q *KKQuery[T], seed A, fn func(acc A, item T) A) A {
+	acc, _ := FoldCtx(context.Background(), q, seed, fn)
+	return acc
+}
+
+// FoldErr is like Fold and also returns the error reported by the query chain, if any.
+func FoldErr[T any, A any](q *KKQuery[T], seed A, fn func(acc A, item T) A) (A, error) {
+	return FoldCtx(context.Background(), q, seed, fn)
+}
+
+// FoldCtx is like FoldErr, stopping with ctx.Err() if ctx is cancelled.
+func FoldCtx[T any, A any](ctx context.Context, q *KKQuery[T], seed A, fn func(acc A, item T) A) (A, error) {
+	r := newRun(ctx)
+	defer r.close()
+	iter := q.iterate(r)
+
+	acc := seed
+	for !r.done() {
+		item, ok := iter()
+		if !ok {
+			break
+		}
+		acc = fn(acc, item)
+	}
+	return acc, r.err()
+}
+
+// Stats summarizes values in one pass.
+type Stats[N Number] struct {
+	Count int
+	Sum   N
+	Min   N
+	Max   N
+	// Mean is computed in float64 and is 0 when Count is 0.
+	Mean float64
+}
+
+// Summarize computes the count, sum, min, max and mean of values produced by
+// the selector function in a single pass over the query. For an empty query
+// every field is zero.
+func Summarize[T any, N Number](q *KKQuery[T], selector func(T) N) Stats[N] {
+	stats, _ := SummarizeCtx(context.Background(), q, selector)
+	return stats
+}
+
+// SummarizeErr is like Summarize and also returns the error reported by the query chain, if any.
+func SummarizeErr[T any, N Number](q *KKQuery[T], selector func(T) N) (Stats[N], error) {
+	return SummarizeCtx(context.Background(), q, selector)
+}
+
+// SummarizeCtx is like SummarizeErr, stopping with ctx.Err() if ctx is cancelled.
+func SummarizeCtx[T any, N Number](ctx context.Context, q *KKQuery[T], selector func(T) N) (Stats[N], error) {
+	r := newRun(ctx)
+	defer r.close()
+	iter := q.iterate(r)
+
+	var stats Stats[N]
+	var total float64
+	for !r.done() {
+		item, ok := iter()
+		if !ok {
+			break
+		}
+		value := selector(item)
+		if stats.Count == 0 || value < stats.Min {
+			stats.Min = value
+		}
+		if stats.Count == 0 || value > stats.Max {
+			stats.Max = value
+		}
+		stats.Count++
+		stats.Sum += value
+		total += float64(value)
+	}
+	if stats.Count > 0 {
+		stats.Mean = total / float64(stats.Count)
+	}
+	return stats, r.err()
+}
+
 // First returns the first item, or the zero value if the query is empty.
 // The second return value indicates whether an item was found.
 func First[T any](q *KKQuery[T]) (T, bool) {
-	iter := q.iterate()
+	r := newRun(context.Background())
+	defer r.close()
+	iter := q.iterate(r)
 	return iter()
 }
 
 // Any returns true if any item matches the predicate.
 func Any[T any](q *KKQuery[T], predicate func(T) bool) bool {
-	iter := q.iterate()
+	r := newRun(context.Background())
+	defer r.close()
+	iter := q.iterate(r)
 	for {
 		item, ok := iter()
 		if !ok {
@@ -63,7 +336,9 @@ func Any[T any](q *KKQuery[T], predicate func(T) bool) bool {
 // All returns true if all items match the predicate.
 // Returns true for empty queries.
 func All[T any](q *KKQuery[T], predicate func(T) bool) bool {
-	iter := q.iterate()
+	r := newRun(context.Background())
+	defer r.close()
+	iter := q.iterate(r)
 	for {
 		item, ok := iter()
 		if !ok {
@@ -77,7 +352,9 @@ func All[T any](q *KKQuery[T], predicate func(T) bool) bool {
 
 // Print prints all items in the query (for debugging).
 func Print[T any](q *KKQuery[T]) {
-	iter := q.iterate()
+	r := newRun(context.Background())
+	defer r.close()
+	iter := q.iterate(r)
 	for {
 		item, ok := iter()
 		if !ok {
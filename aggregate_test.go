@@ -1,6 +1,8 @@
 package kk
 
 import (
+	"context"
+	"errors"
 	"testing"
 )
 
@@ -168,3 +170,224 @@ func TestAllNoneMatch(t *testing.T) {
 		t.Error("expected false")
 	}
 }
+
+func TestCountErr(t *testing.T) {
+	expectedErr := errors.New("test error")
+	q := MappedErr(
+		Query([]int{1, 2, 3, 4}), func(n int) (int, error) {
+			if n == 3 {
+				return 0, expectedErr
+			}
+			return n, nil
+		},
+	)
+
+	count, err := CountErr(q)
+	if err != expectedErr {
+		t.Errorf("expected %v, got %v", expectedErr, err)
+	}
+	if count != 2 {
+		t.Errorf("expected count 2, got %d", count)
+	}
+
+	count, err = CountErr(q.OnError(CollectErrors))
+	if err != expectedErr {
+		t.Errorf("expected %v, got %v", expectedErr, err)
+	}
+	if count != 3 {
+		t.Errorf("expected count 3, got %d", count)
+	}
+}
+
+func TestCountCtx(t *testing.T) {
+	count, err := CountCtx(context.Background(), Query([]int{1, 2, 3}))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if count != 3 {
+		t.Errorf("expected count 3, got %d", count)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = CountCtx(ctx, Query([]int{1, 2, 3}))
+	if err != context.Canceled {
+		t.Errorf("expected %v, got %v", context.Canceled, err)
+	}
+}
+
+func TestMinMax(t *testing.T) {
+	q := Query([]int{3, 1, 4, 1, 5})
+
+	if v, ok := Min(q); !ok || v != 1 {
+		t.Errorf("expected min 1, got %d (%v)", v, ok)
+	}
+	if v, ok := Max(q); !ok || v != 5 {
+		t.Errorf("expected max 5, got %d (%v)", v, ok)
+	}
+}
+
+func TestMinMaxEmpty(t *testing.T) {
+	q := Query([]string{})
+
+	if v, ok := Min(q); ok || v != "" {
+		t.Errorf("expected no min, got %q (%v)", v, ok)
+	}
+	if v, ok := Max(q); ok || v != "" {
+		t.Errorf("expected no max, got %q (%v)", v, ok)
+	}
+}
+
+func TestMinByMaxBy(t *testing.T) {
+	type person struct {
+		Name string
+		Age  int
+	}
+	q := Query([]person{{"ann", 30}, {"bob", 20}, {"cid", 40}, {"dee", 20}, {"eve", 40}})
+	age := func(p person) int { return p.Age }
+
+	if p, ok := MinBy(q, age); !ok || p.Name != "bob" {
+		t.Errorf("expected bob, got %v (%v)", p, ok)
+	}
+	if p, ok := MaxBy(q, age); !ok || p.Name != "cid" {
+		t.Errorf("expected cid, got %v (%v)", p, ok)
+	}
+	if _, ok := MaxBy(Query([]person{}), age); ok {
+		t.Error("expected no item for empty query")
+	}
+}
+
+func TestAverage(t *testing.T) {
+	result, ok := Average(Query([]int{1, 2, 3, 4}), func(n int) int { return n })
+
+	if !ok || result != 2.5 {
+		t.Errorf("expected 2.5, got %v (%v)", result, ok)
+	}
+}
+
+func TestAverageEmpty(t *testing.T) {
+	result, ok := Average(Query([]float64{}), func(n float64) float64 { return n })
+
+	if ok || result != 0 {
+		t.Errorf("expected 0 and false, got %v (%v)", result, ok)
+	}
+}
+
+func TestReduce(t *testing.T) {
+	result, ok := Reduce(Query([]int{1, 2, 3, 4}), func(acc int, n int) int { return acc * n })
+
+	if !ok || result != 24 {
+		t.Errorf("expected 24, got %d (%v)", result, ok)
+	}
+	if _, ok := Reduce(Query([]int{}), func(acc int, n int) int { return acc + n }); ok {
+		t.Error("expected no result for empty query")
+	}
+}
+
+func TestFold(t *testing.T) {
+	result := Fold(
+		Query([]string{"a", "bb", "ccc"}), 10, func(acc int, s string) int {
+			return acc + len(s)
+		},
+	)
+
+	if result != 16 {
+		t.Errorf("expected 16, got %d", result)
+	}
+	if result := Fold(Query([]string{}), 10, func(acc int, s string) int { return 0 }); result != 10 {
+		t.Errorf("expected seed 10, got %d", result)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	pulls := 0
+	q := Mapped(
+		Query([]int{4, -2, 7, 3}), func(n int) int {
+			pulls++
+			return n
+		},
+	)
+	stats := Summarize(q, func(n int) int { return n })
+
+	if pulls != 4 {
+		t.Errorf("expected one pass of 4 items, got %d", pulls)
+	}
+	expected := Stats[int]{Count: 4, Sum: 12, Min: -2, Max: 7, Mean: 3}
+	if stats != expected {
+		t.Errorf("expected %+v, got %+v", expected, stats)
+	}
+}
+
+func TestSummarizeEmpty(t *testing.T) {
+	stats := Summarize(Query([]int{}), func(n int) int { return n })
+
+	if stats != (Stats[int]{}) {
+		t.Errorf("expected zero stats, got %+v", stats)
+	}
+}
+
+func TestAggregatesReportErrors(t *testing.T) {
+	q := MappedErr(Query([]int{1, 2, 3}), failOn(2))
+	identity := func(n int) int { return n }
+	sum := func(acc int, n int) int { return acc + n }
+	checkErr := func(name string, err error) {
+		t.Helper()
+		if err == nil || err.Error() != "bad item 2" {
+			t.Errorf("%s: expected bad item 2, got %v", name, err)
+		}
+	}
+
+	_, _, err := MinErr(q)
+	checkErr("MinErr", err)
+	_, _, err = MaxErr(q)
+	checkErr("MaxErr", err)
+	_, _, err = MinByErr(q, identity)
+	checkErr("MinByErr", err)
+	_, _, err = MaxByErr(q, identity)
+	checkErr("MaxByErr", err)
+	_, _, err = AverageErr(q, identity)
+	checkErr("AverageErr", err)
+	_, _, err = ReduceErr(q, sum)
+	checkErr("ReduceErr", err)
+	_, err = FoldErr(q, 0, sum)
+	checkErr("FoldErr", err)
+	_, err = SummarizeErr(q, identity)
+	checkErr("SummarizeErr", err)
+}
+
+func TestMaxErrPartial(t *testing.T) {
+	v, ok, err := MaxErr(MappedErr(Query([]int{1, 5, 3}), failOn(3)))
+
+	// The result is built from the items before the error
+	if !ok || v != 5 {
+		t.Errorf("expected 5 from the items before the error, got %d (%v)", v, ok)
+	}
+	if err == nil {
+		t.Error("expected error, got nil")
+	}
+}
+
+func TestAggregatesCollectErrors(t *testing.T) {
+	q := MappedErr(Query([]int{1, 2, 3, 4}), failOn(2)).OnError(CollectErrors)
+	stats, err := SummarizeErr(q, func(n int) int { return n })
+
+	if stats.Count != 3 || stats.Sum != 8 {
+		t.Errorf("expected 3 items summing to 8, got %+v", stats)
+	}
+	if err == nil || err.Error() != "bad item 2" {
+		t.Errorf("expected bad item 2, got %v", err)
+	}
+}
+
+func TestAggregatesCtx(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	q := Query([]int{1, 2, 3})
+
+	if _, ok, err := MinCtx(ctx, q); ok || err != context.Canceled {
+		t.Errorf("expected no result and %v, got %v (%v)", context.Canceled, err, ok)
+	}
+	if acc, err := FoldCtx(ctx, q, 10, func(acc int, n int) int { return acc + n }); acc != 10 || err != context.Canceled {
+		t.Errorf("expected seed and %v, got %d and %v", context.Canceled, acc, err)
+	}
+}
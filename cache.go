@@ -0,0 +1,297 @@
+package kk
+
+import (
+	"context"
+	"math"
+	"sync"
+)
+
+// CachedQuery is a query returned by Cached and CachedLazy. It can be used
+// like any other query, and Close releases the upstream it holds between
+// iterations.
+type CachedQuery[T any] struct {
+	*KKQuery[T]
+	cache *lazyCache[T]
+}
+
+// Cached materializes the query on first iteration and replays the stored
+// items on every iteration after that, so upstream work runs only once.
+// Concurrent iterations share the same materialization.
+// Upstream is iterated in a background goroutine, so an iteration waiting for
+// it still ends promptly when its context is cancelled. Upstream keeps running
+// and the next iteration picks up where it left off. Call Close to stop
+// upstream and release its resources before it is exhausted.
+// A panic upstream is raised again in every iteration until Close.
+func (q *KKQuery[T]) Cached() *CachedQuery[T] {
+	c := newLazyCache(q)
+	return &CachedQuery[T]{
+		KKQuery: &KKQuery[T]{
+			iterate: func(r *run) Iterator[T] {
+				c.mu.Lock()
+				gen := c.gen
+				c.mu.Unlock()
+				iter := c.iterator(r)
+				loaded := false
+				return func() (T, bool) {
+					if !loaded {
+						loaded = true
+						// Wait for upstream to be exhausted before replaying
+						if !c.wait(r, gen, math.MaxInt-1) {
+							var zero T
+							return zero, false
+						}
+					}
+					return iter()
+				}
+			},
+		},
+		cache: c,
+	}
+}
+
+// CachedLazy caches items incrementally as they are pulled. Upstream is only
+// advanced when an iteration needs an item that has not been cached yet, so
+// Take and First do not force the full sequence.
+// Concurrent iterations are safe and share a single upstream iterator, which
+// is pulled from a background goroutine only while an iteration is waiting
+// for an item. An iteration waiting for upstream ends promptly when its
+// context is cancelled, without losing the item upstream is producing.
+// A panic upstream is raised again in every iteration until Close.
+// Upstream resources stay open until upstream is exhausted or the cached
+// query is closed, so close it when it may not be fully iterated:
+//
+//	lines := kk.QueryLines(f).CachedLazy()
+//	defer lines.Close()
+//	header, _ := kk.First(lines.KKQuery)
+func (q *KKQuery[T]) CachedLazy() *CachedQuery[T] {
+	c := newLazyCache(q)
+	return &CachedQuery[T]{
+		KKQuery: &KKQuery[T]{iterate: c.iterator},
+		cache:   c,
+	}
+}
+
+// Close stops upstream if it is still running and runs its cleanup, such as
+// closing a file. Iterations of the cached query that are in progress end.
+// If upstream was not exhausted the cached items are dropped, and the next
+// iteration starts upstream again. Queries built from the cached query do
+// not have Close, so keep the cached query to close it:
+//
+//	lines := kk.QueryLines(f).CachedLazy()
+//	defer lines.Close()
+//	matches := lines.Where(isMatch)
+func (q *CachedQuery[T]) Close() {
+	q.cache.close()
+}
+
+// lazyCache holds the items pulled so far from a shared upstream iterator.
+// A filler goroutine pulls from upstream only while some iteration needs more
+// items than are cached, and exits once they are; iterations wait for it
+// without holding the mutex.
+type lazyCache[T any] struct {
+	source *KKQuery[T]
+
+	mu       sync.Mutex
+	items    []T
+	done     bool
+	err      error
+	panicked bool
+	panicVal any
+	needed   int               // number of items some iteration is waiting for
+	gen      int               // incremented when Close drops the cached items
+	upstream *cacheUpstream[T] // nil before the first pull and once upstream ends
+	filling  bool              // whether a filler goroutine is pulling from upstream
+	changed  chan struct{}     // closed and replaced when items, done or gen change
+}
+
+// cacheUpstream is one iteration of the cache's source. It outlives the
+// filler goroutines that pull from it, one at a time, until it is exhausted
+// or the cache is closed.
+type cacheUpstream[T any] struct {
+	r       *run
+	next    Iterator[T]
+	cancel  context.CancelFunc
+	fillers sync.WaitGroup
+}
+
+func newLazyCache[T any](source *KKQuery[T]) *lazyCache[T] {
+	return &lazyCache[T]{
+		source:  source,
+		changed: make(chan struct{}),
+	}
+}
+
+// iterator returns an iterator over the cached items, pulling from upstream
+// as needed. Once upstream is exhausted it reports the error upstream reported.
+func (c *lazyCache[T]) iterator(r *run) Iterator[T] {
+	c.mu.Lock()
+	gen := c.gen
+	c.mu.Unlock()
+	index := 0
+	reported := false
+	return func() (T, bool) {
+		var zero T
+		if !c.wait(r, gen, index) {
+			return zero, false
+		}
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if c.gen != gen {
+			return zero, false
+		}
+		if index < len(c.items) {
+			item := c.items[index]
+			index++
+			return item, true
+		}
+		// Replay the upstream error after the items that preceded it
+		if c.err != nil && !reported {
+			reported = true
+			r.fail(c.err)
+		}
+		return zero, false
+	}
+}
+
+// wait blocks until the cache holds the item at index or upstream is
+// exhausted. It returns false if the run's context is done first, or if the
+// cache was closed since generation gen. If upstream panicked, wait panics
+// with the same value on the calling goroutine.
+func (c *lazyCache[T]) wait(r *run, gen int, index int) bool {
+	for {
+		c.mu.Lock()
+		if c.gen != gen {
+			c.mu.Unlock()
+			return false
+		}
+		if c.panicked {
+			p := c.panicVal
+			c.mu.Unlock()
+			panic(p)
+		}
+		if index < len(c.items) || c.done {
+			c.mu.Unlock()
+			return true
+		}
+		if index >= c.needed {
+			c.needed = index + 1
+		}
+		if !c.filling {
+			if c.upstream == nil {
+				// Upstream is not bound to any one iteration's context
+				ctx, cancel := context.WithCancel(context.Background())
+				c.upstream = &cacheUpstream[T]{r: r.detached(ctx), cancel: cancel}
+			}
+			c.filling = true
+			c.upstream.fillers.Add(1)
+			go c.fill(c.upstream)
+		}
+		changed := c.changed
+		c.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-r.ctx.Done():
+			r.done()
+			return false
+		}
+	}
+}
+
+// fill pulls from u while iterations need more items than are cached, then
+// returns. When u is exhausted or panics, fill runs its cleanup and records
+// the outcome for waiting iterations.
+func (c *lazyCache[T]) fill(u *cacheUpstream[T]) {
+	defer u.fillers.Done()
+	defer func() {
+		if p := recover(); p != nil {
+			u.r.close()
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			if c.upstream == u {
+				c.upstream = nil
+				c.filling = false
+				c.panicked = true
+				c.panicVal = p
+				c.notify()
+			}
+		}
+	}()
+	for {
+		c.mu.Lock()
+		if c.upstream != u || len(c.items) >= c.needed {
+			if c.upstream == u {
+				c.filling = false
+			}
+			c.mu.Unlock()
+			return
+		}
+		c.mu.Unlock()
+
+		if u.next == nil {
+			u.next = c.source.iterate(u.r)
+		}
+		item, ok := u.next()
+		if u.r.ctx.Err() != nil {
+			// Closed: the result of an interrupted pull is not a real end
+			return
+		}
+		if !ok {
+			u.r.close()
+		}
+
+		c.mu.Lock()
+		if c.upstream != u {
+			c.mu.Unlock()
+			return
+		}
+		if ok {
+			c.items = append(c.items, item)
+		} else {
+			c.done = true
+			c.err = u.r.err()
+			c.upstream = nil
+			c.filling = false
+			u.cancel()
+		}
+		c.notify()
+		c.mu.Unlock()
+		if !ok {
+			return
+		}
+	}
+}
+
+// close stops the filler goroutine, if any, and runs upstream cleanup.
+// Unless upstream was exhausted, the cached items are dropped and iterations
+// in progress end.
+func (c *lazyCache[T]) close() {
+	c.mu.Lock()
+	u := c.upstream
+	if c.done || (u == nil && !c.panicked) {
+		// Nothing started, already closed, or nothing to drop
+		c.mu.Unlock()
+		return
+	}
+	c.upstream = nil
+	c.filling = false
+	c.panicked = false
+	c.panicVal = nil
+	c.items = nil
+	c.needed = 0
+	c.gen++
+	c.notify()
+	c.mu.Unlock()
+
+	if u != nil {
+		u.cancel()
+		u.fillers.Wait()
+		u.r.close()
+	}
+}
+
+// notify wakes iterations waiting for the cache to change. c.mu must be held.
+func (c *lazyCache[T]) notify() {
+	close(c.changed)
+	c.changed = make(chan struct{})
+}
@@ -0,0 +1,361 @@
+package kk
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCached(t *testing.T) {
+	var calls atomic.Int32
+	q := Mapped(
+		Query([]int{1, 2, 3}), func(n int) int {
+			calls.Add(1)
+			return n * 10
+		},
+	).Cached()
+
+	if Count(q.KKQuery) != 3 {
+		t.Errorf("expected count 3, got %d", Count(q.KKQuery))
+	}
+	result := Slice(q.KKQuery)
+
+	expected := []int{10, 20, 30}
+	if len(result) != len(expected) {
+		t.Fatalf("expected length %d, got %d", len(expected), len(result))
+	}
+	for i, v := range result {
+		if v != expected[i] {
+			t.Errorf("index %d: expected %d, got %d", i, expected[i], v)
+		}
+	}
+	if calls.Load() != 3 {
+		t.Errorf("expected upstream to run 3 times, got %d", calls.Load())
+	}
+}
+
+func TestCachedEmpty(t *testing.T) {
+	q := Query([]int{}).Cached()
+
+	if result := Slice(q.KKQuery); len(result) != 0 {
+		t.Errorf("expected empty slice, got %v", result)
+	}
+}
+
+func TestCachedConcurrent(t *testing.T) {
+	var calls atomic.Int32
+	q := Mapped(
+		Query([]int{1, 2, 3, 4, 5}), func(n int) int {
+			calls.Add(1)
+			return n
+		},
+	).Cached()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if sum := Sum(q.KKQuery, func(n int) int { return n }); sum != 15 {
+				t.Errorf("expected sum 15, got %d", sum)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if calls.Load() != 5 {
+		t.Errorf("expected upstream to run 5 times, got %d", calls.Load())
+	}
+}
+
+func TestCachedLazyTake(t *testing.T) {
+	var calls atomic.Int32
+	q := Mapped(
+		Query([]int{1, 2, 3, 4, 5}), func(n int) int {
+			calls.Add(1)
+			return n
+		},
+	).CachedLazy()
+
+	result := Slice(q.Take(2))
+	if len(result) != 2 || result[0] != 1 || result[1] != 2 {
+		t.Errorf("expected [1 2], got %v", result)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("expected upstream to run 2 times, got %d", calls.Load())
+	}
+
+	result = Slice(q.KKQuery)
+	if len(result) != 5 {
+		t.Errorf("expected length 5, got %d", len(result))
+	}
+	if calls.Load() != 5 {
+		t.Errorf("expected upstream to run 5 times, got %d", calls.Load())
+	}
+
+	if Count(q.KKQuery) != 5 {
+		t.Errorf("expected count 5, got %d", Count(q.KKQuery))
+	}
+	if calls.Load() != 5 {
+		t.Errorf("expected upstream to run 5 times after replay, got %d", calls.Load())
+	}
+}
+
+func TestCachedLazyConcurrent(t *testing.T) {
+	var calls atomic.Int32
+	input := make([]int, 100)
+	for i := range input {
+		input[i] = i
+	}
+	q := Mapped(
+		Query(input), func(n int) int {
+			calls.Add(1)
+			return n
+		},
+	).CachedLazy()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result := Slice(q.KKQuery)
+			for j, v := range result {
+				if v != j {
+					t.Errorf("index %d: expected %d, got %d", j, j, v)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if calls.Load() != 100 {
+		t.Errorf("expected upstream to run 100 times, got %d", calls.Load())
+	}
+}
+
+func TestCachedCancelled(t *testing.T) {
+	ch := make(chan int, 1)
+	q := QueryChan(ch).Cached()
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	ch <- 1
+	done := make(chan error, 1)
+	go func() {
+		_, err := SliceCtx(ctx, q.KKQuery)
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err != context.DeadlineExceeded {
+			t.Errorf("expected %v, got %v", context.DeadlineExceeded, err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the iteration to end when its context was done")
+	}
+
+	// The cancelled iteration did not mark the cache complete
+	ch <- 2
+	close(ch)
+	if result := Slice(q.KKQuery); len(result) != 2 || result[0] != 1 || result[1] != 2 {
+		t.Errorf("expected [1 2], got %v", result)
+	}
+}
+
+func TestCachedLazyCancelled(t *testing.T) {
+	ch := make(chan int)
+	q := QueryChan(ch).CachedLazy()
+	sliceWithin := func(d time.Duration) ([]int, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), d)
+		defer cancel()
+		return SliceCtx(ctx, q.KKQuery)
+	}
+
+	// Nothing has been sent yet, so the iteration ends when its context is done
+	if result, err := sliceWithin(20 * time.Millisecond); err != context.DeadlineExceeded || len(result) != 0 {
+		t.Errorf("expected no items and %v, got %v and %v", context.DeadlineExceeded, result, err)
+	}
+
+	// The item upstream was waiting for when the iteration ended is not lost
+	ch <- 1
+	if result, err := sliceWithin(20 * time.Millisecond); err != context.DeadlineExceeded || len(result) != 1 {
+		t.Errorf("expected [1] and %v, got %v and %v", context.DeadlineExceeded, result, err)
+	}
+
+	// Cached items can be read while upstream is blocked waiting for the next one
+	readCached := make(chan int, 1)
+	go func() {
+		item, _ := First(q.KKQuery)
+		readCached <- item
+	}()
+	select {
+	case item := <-readCached:
+		if item != 1 {
+			t.Errorf("expected 1, got %d", item)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected cached items to be readable while upstream is blocked")
+	}
+
+	ch <- 2
+	close(ch)
+	if result := Slice(q.KKQuery); len(result) != 2 || result[1] != 2 {
+		t.Errorf("expected [1 2], got %v", result)
+	}
+}
+
+func TestCachedLazyClose(t *testing.T) {
+	rd := &closeTracker{Reader: strings.NewReader("a\nb\nc\n")}
+	lines := QueryLines(rd).CachedLazy()
+
+	if line, _ := First(lines.KKQuery); line != "a" {
+		t.Errorf("expected a, got %q", line)
+	}
+	if rd.closed != 0 {
+		t.Errorf("expected upstream to stay open between iterations, got %d closes", rd.closed)
+	}
+
+	lines.Close()
+	if rd.closed != 1 {
+		t.Errorf("expected upstream closed once, got %d closes", rd.closed)
+	}
+	lines.Close()
+	if rd.closed != 1 {
+		t.Errorf("expected a second Close to do nothing, got %d closes", rd.closed)
+	}
+}
+
+func TestCachedLazyCloseRestarts(t *testing.T) {
+	var calls atomic.Int32
+	q := Mapped(
+		Query([]int{1, 2, 3}), func(n int) int {
+			calls.Add(1)
+			return n
+		},
+	).CachedLazy()
+
+	Slice(q.Take(1))
+	q.Close()
+
+	// The partial cache was dropped, so upstream runs again from the start
+	if result := Slice(q.KKQuery); len(result) != 3 || result[0] != 1 {
+		t.Errorf("expected [1 2 3], got %v", result)
+	}
+	if calls.Load() != 4 {
+		t.Errorf("expected upstream to run 4 times, got %d", calls.Load())
+	}
+
+	// An exhausted cache is kept
+	q.Close()
+	if n := Count(q.KKQuery); n != 3 || calls.Load() != 4 {
+		t.Errorf("expected 3 cached items without upstream work, got %d after %d calls", n, calls.Load())
+	}
+}
+
+func TestCachedCloseEndsWaitingIteration(t *testing.T) {
+	closed := make(chan struct{})
+	q := QueryChan(make(chan int)).OnClose(func() { close(closed) }).Cached()
+
+	done := make(chan int, 1)
+	go func() {
+		done <- Count(q.KKQuery)
+	}()
+	// Close does nothing until the iteration has started upstream, so retry
+	deadline := time.After(time.Second)
+	for upstreamClosed := false; !upstreamClosed; {
+		q.Close()
+		select {
+		case <-closed:
+			upstreamClosed = true
+		case <-time.After(time.Millisecond):
+		case <-deadline:
+			t.Fatal("expected Close to stop upstream")
+		}
+	}
+
+	select {
+	case n := <-done:
+		if n != 0 {
+			t.Errorf("expected no items, got %d", n)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the waiting iteration to end on Close")
+	}
+}
+
+func TestCachedPanicReachesCaller(t *testing.T) {
+	panicsOn2 := func(n int) int {
+		if n == 2 {
+			panic("boom")
+		}
+		return n
+	}
+	for _, q := range []*CachedQuery[int]{
+		Mapped(Query([]int{1, 2}), panicsOn2).Cached(),
+		Mapped(Query([]int{1, 2}), panicsOn2).CachedLazy(),
+	} {
+		// Every iteration raises the panic again, not only the first
+		for i := 0; i < 2; i++ {
+			func() {
+				defer func() {
+					if p := recover(); p != "boom" {
+						t.Errorf("expected panic boom, got %v", p)
+					}
+				}()
+				Slice(q.KKQuery)
+			}()
+		}
+	}
+}
+
+func TestCachedLazyPanicClosesUpstream(t *testing.T) {
+	closed := 0
+	q := Mapped(
+		Query([]int{1, 2, 3}).OnClose(func() { closed++ }), func(n int) int {
+			if n == 2 {
+				panic("boom")
+			}
+			return n
+		},
+	).CachedLazy()
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected panic")
+			}
+		}()
+		Slice(q.KKQuery)
+	}()
+	if closed != 1 {
+		t.Errorf("expected upstream closed once, got %d closes", closed)
+	}
+
+	// Close drops the panic, so the next iteration starts upstream again
+	q.Close()
+	if item, ok := First(q.KKQuery); !ok || item != 1 {
+		t.Errorf("expected 1, got %d", item)
+	}
+}
+
+func TestCachedLazyPartialIterationNoLeak(t *testing.T) {
+	before := runtime.NumGoroutine()
+	for i := 0; i < 100; i++ {
+		First(Query([]int{1, 2, 3}).CachedLazy().KKQuery)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if n := runtime.NumGoroutine(); n > before {
+		t.Errorf("expected fillers to exit, %d goroutines remain above baseline", n-before)
+	}
+}
@@ -0,0 +1,43 @@
+package kk
+
+import "context"
+
+// ToChan starts a goroutine that iterates the query and sends each item to
+// the returned channel, which has the given buffer size and is closed when
+// iteration ends. The goroutine stops when ctx is cancelled, so a consumer
+// that stops reading early must cancel ctx to release it.
+func ToChan[T any](ctx context.Context, q *KKQuery[T], buffer int) <-chan T {
+	out, _ := ToChanErr(ctx, q, buffer)
+	return out
+}
+
+// ToChanErr is like ToChan but also returns a channel that receives the error
+// reported by the query chain, or nil, once the item channel is closed.
+// The error channel is buffered, so it does not need to be read.
+func ToChanErr[T any](ctx context.Context, q *KKQuery[T], buffer int) (<-chan T, <-chan error) {
+	out := make(chan T, buffer)
+	errc := make(chan error, 1)
+
+	go func() {
+		r := newRun(ctx)
+		func() {
+			defer r.close()
+			iter := q.iterate(r)
+			for !r.done() {
+				item, ok := iter()
+				if !ok {
+					break
+				}
+				select {
+				case out <- item:
+				case <-ctx.Done():
+				}
+			}
+		}()
+		close(out)
+		errc <- r.err()
+		close(errc)
+	}()
+
+	return out, errc
+}
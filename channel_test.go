@@ -0,0 +1,129 @@
+package kk
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestToChan(t *testing.T) {
+	ch := ToChan(context.Background(), Query([]int{1, 2, 3}), 0)
+
+	var result []int
+	for n := range ch {
+		result = append(result, n)
+	}
+	if len(result) != 3 || result[0] != 1 || result[2] != 3 {
+		t.Errorf("expected [1 2 3], got %v", result)
+	}
+}
+
+func TestToChanBuffer(t *testing.T) {
+	var pulled atomic.Int32
+	q := Mapped(
+		Range(0, 100, 1), func(n int) int {
+			pulled.Add(1)
+			return n
+		},
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch := ToChan(ctx, q, 5)
+	time.Sleep(20 * time.Millisecond)
+
+	// Five items buffered and one waiting to be sent
+	if n := pulled.Load(); n != 6 {
+		t.Errorf("expected 6 items pulled, got %d", n)
+	}
+	if len(ch) != 5 {
+		t.Errorf("expected 5 buffered items, got %d", len(ch))
+	}
+}
+
+func TestToChanErr(t *testing.T) {
+	expectedErr := errors.New("test error")
+	q := MappedErr(
+		Query([]int{1, 2, 3}), func(n int) (int, error) {
+			if n == 3 {
+				return 0, expectedErr
+			}
+			return n, nil
+		},
+	)
+	ch, errc := ToChanErr(context.Background(), q, 0)
+
+	count := 0
+	for range ch {
+		count++
+	}
+	if count != 2 {
+		t.Errorf("expected 2 items, got %d", count)
+	}
+	if err := <-errc; err != expectedErr {
+		t.Errorf("expected %v, got %v", expectedErr, err)
+	}
+}
+
+func TestToChanCancelStopsProducer(t *testing.T) {
+	closed := make(chan struct{})
+	q := Generate(func(i int) int { return i }).OnClose(func() { close(closed) })
+	ctx, cancel := context.WithCancel(context.Background())
+
+	ch, errc := ToChanErr(ctx, q, 0)
+	<-ch
+	<-ch
+	cancel()
+
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatal("expected producer to stop and close the source")
+	}
+	if err := <-errc; err != context.Canceled {
+		t.Errorf("expected %v, got %v", context.Canceled, err)
+	}
+}
+
+func TestToChanNoLeak(t *testing.T) {
+	before := runtime.NumGoroutine()
+	for i := 0; i < 10; i++ {
+		ctx, cancel := context.WithCancel(context.Background())
+		ch := ToChan(ctx, Generate(func(i int) int { return i }), 0)
+		<-ch
+		cancel()
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if n := runtime.NumGoroutine(); n > before {
+		t.Errorf("expected producers to exit, %d goroutines remain above baseline", n-before)
+	}
+}
+
+func TestToChanFeedsParallelByBatchChan(t *testing.T) {
+	var sum atomic.Int64
+	q := Range(1, 101, 1).Where(func(n int) bool { return n%2 == 0 })
+
+	err := ParallelByBatchChan(
+		context.Background(), ToChan(context.Background(), q, 10), 7, 3,
+		func(ctx context.Context, batch []int) error {
+			for _, n := range batch {
+				sum.Add(int64(n))
+			}
+			return nil
+		},
+	)
+
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if sum.Load() != 2550 {
+		t.Errorf("expected sum 2550, got %d", sum.Load())
+	}
+}
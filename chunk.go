@@ -4,8 +4,8 @@ package kk
 // This is a function (not a method) because it returns a different type.
 func Chunk[T any](q *KKQuery[T], size int) *KKQuery[[]T] {
 	return &KKQuery[[]T]{
-		iterate: func() Iterator[[]T] {
-			iter := q.iterate()
+		iterate: func(r *run) Iterator[[]T] {
+			iter := q.iterate(r)
 			done := false
 			return func() ([]T, bool) {
 				if done {
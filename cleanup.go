@@ -0,0 +1,17 @@
+package kk
+
+// OnClose registers fn to run exactly once when an iteration of the query
+// ends, whether it completes, stops early (Take, First, Any, TakeWhile) or
+// panics. Use it to release resources held by a source:
+//
+//	q := kk.QueryChan(ch).OnClose(stopProducer)
+//
+// Cleanup registered further down the chain runs first.
+func (q *KKQuery[T]) OnClose(fn func()) *KKQuery[T] {
+	return &KKQuery[T]{
+		iterate: func(r *run) Iterator[T] {
+			r.onClose(fn)
+			return q.iterate(r)
+		},
+	}
+}
@@ -0,0 +1,226 @@
+package kk
+
+import (
+	"context"
+	"testing"
+)
+
+func TestOnCloseCompletes(t *testing.T) {
+	closed := 0
+	q := Query([]int{1, 2, 3}).OnClose(func() { closed++ })
+
+	if result := Slice(q); len(result) != 3 {
+		t.Errorf("expected length 3, got %d", len(result))
+	}
+	if closed != 1 {
+		t.Errorf("expected 1 close, got %d", closed)
+	}
+
+	Slice(q)
+	if closed != 2 {
+		t.Errorf("expected 1 close per iteration, got %d", closed)
+	}
+}
+
+func TestOnCloseEarlyStop(t *testing.T) {
+	closed := 0
+	q := Query([]int{1, 2, 3, 4}).OnClose(func() { closed++ })
+
+	Slice(q.Take(1))
+	First(q)
+	Any(q, func(n int) bool { return n == 2 })
+	Slice(q.TakeWhile(func(n int) bool { return n < 2 }))
+
+	if closed != 4 {
+		t.Errorf("expected 4 closes, got %d", closed)
+	}
+}
+
+func TestOnClosePanic(t *testing.T) {
+	closed := 0
+	q := Mapped(
+		Query([]int{1, 2, 3}).OnClose(func() { closed++ }), func(n int) int {
+			if n == 2 {
+				panic("boom")
+			}
+			return n
+		},
+	)
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected panic")
+			}
+		}()
+		Slice(q)
+	}()
+
+	if closed != 1 {
+		t.Errorf("expected 1 close, got %d", closed)
+	}
+}
+
+func TestOnCloseOrder(t *testing.T) {
+	var order []string
+	q := Query([]int{1}).
+		OnClose(func() { order = append(order, "source") }).
+		Where(func(int) bool { return true }).
+		OnClose(func() { order = append(order, "outer") })
+
+	Slice(q)
+	if len(order) != 2 || order[0] != "source" || order[1] != "outer" {
+		t.Errorf("expected [source outer], got %v", order)
+	}
+}
+
+func TestOnCloseConcatAndUnion(t *testing.T) {
+	closed := map[string]int{}
+	q1 := Query([]int{1, 2}).OnClose(func() { closed["q1"]++ })
+	q2 := Query([]int{2, 3}).OnClose(func() { closed["q2"]++ })
+
+	Slice(q1.Concat(q2).Take(1))
+	Slice(q1.Union(q2))
+
+	if closed["q1"] != 2 || closed["q2"] != 2 {
+		t.Errorf("expected each query closed twice, got %v", closed)
+	}
+}
+
+func TestFlattenedQuery(t *testing.T) {
+	closed := 0
+	var closedBefore []int
+	q := Mapped(
+		FlattenedQuery(
+			Query([]int{1, 2, 3}), func(n int) *KKQuery[int] {
+				return Query([]int{n, n * 10}).OnClose(func() { closed++ })
+			},
+		), func(n int) int {
+			closedBefore = append(closedBefore, closed)
+			return n
+		},
+	)
+	result := Slice(q)
+
+	expected := []int{1, 10, 2, 20, 3, 30}
+	if len(result) != len(expected) {
+		t.Fatalf("expected length %d, got %d", len(expected), len(result))
+	}
+	for i, v := range result {
+		if v != expected[i] {
+			t.Errorf("index %d: expected %d, got %d", i, expected[i], v)
+		}
+	}
+
+	// Each inner query is closed before the next one yields
+	expectedClosed := []int{0, 0, 1, 1, 2, 2}
+	for i, c := range closedBefore {
+		if c != expectedClosed[i] {
+			t.Errorf("item %d: expected %d closed, got %d", i, expectedClosed[i], c)
+		}
+	}
+	if closed != 3 {
+		t.Errorf("expected 3 closes, got %d", closed)
+	}
+}
+
+func TestFlattenedQueryEarlyStop(t *testing.T) {
+	closed := 0
+	q := FlattenedQuery(
+		Query([]int{1, 2, 3}), func(n int) *KKQuery[int] {
+			return Query([]int{n, n}).OnClose(func() { closed++ })
+		},
+	)
+
+	Slice(q.Take(3))
+	if closed != 2 {
+		t.Errorf("expected 2 inner closes, got %d", closed)
+	}
+}
+
+func TestFlattenedQueryZipped(t *testing.T) {
+	var log []string
+	var readAfterClose []string
+	// inner yields two items and records reads after it was closed
+	inner := func(name string) *KKQuery[string] {
+		return &KKQuery[string]{
+			iterate: func(r *run) Iterator[string] {
+				closed := false
+				r.onClose(
+					func() {
+						closed = true
+						log = append(log, "close "+name)
+					},
+				)
+				index := 0
+				return func() (string, bool) {
+					if closed {
+						readAfterClose = append(readAfterClose, name)
+					}
+					if index >= 2 {
+						return "", false
+					}
+					index++
+					return name, true
+				}
+			},
+		}
+	}
+	// a has one inner query of two items, b two inner queries of two items
+	a := FlattenedQuery(Query([]string{"a1", "a2"}), inner)
+	b := FlattenedQuery(Query([]string{"b1", "b2"}), inner)
+	result := Slice(Zip(a, b))
+
+	if len(result) != 4 || result[2] != (Pair[string, string]{"a2", "b2"}) {
+		t.Errorf("expected 4 pairs, got %v", result)
+	}
+	if len(readAfterClose) != 0 {
+		t.Errorf("expected no reads after close, got %v", readAfterClose)
+	}
+	if len(log) != 4 || log[0] != "close a1" || log[1] != "close b1" {
+		t.Errorf("expected each inner query closed once when exhausted, got %v", log)
+	}
+}
+
+func TestOnCloseParallel(t *testing.T) {
+	closed := 0
+	q := Query([]int{1, 2, 3}).OnClose(func() { closed++ })
+
+	_ = Parallel(context.Background(), q, 2, func(context.Context, int) error { return nil })
+	_, _ = ParallelResult(context.Background(), q, 2, func(_ context.Context, n int) (int, error) { return n, nil })
+	_ = ParallelByKey(context.Background(), q, 2, 1, func(n int) int { return n }, func(context.Context, int) error { return nil })
+	_ = ParallelByBatch(context.Background(), q, 2, 2, func(context.Context, []int) error { return nil })
+
+	if closed != 4 {
+		t.Errorf("expected 4 closes, got %d", closed)
+	}
+}
+
+func TestOnCloseAllBreak(t *testing.T) {
+	closed := 0
+	q := Query([]int{1, 2, 3}).OnClose(func() { closed++ })
+
+	for range q.All() {
+		break
+	}
+	if closed != 1 {
+		t.Errorf("expected 1 close, got %d", closed)
+	}
+}
+
+func TestQuerySeqStoppedEarly(t *testing.T) {
+	stopped := false
+	seq := func(yield func(int) bool) {
+		defer func() { stopped = true }()
+		for i := 0; ; i++ {
+			if !yield(i) {
+				return
+			}
+		}
+	}
+
+	Slice(QuerySeq(seq).Take(2))
+	if !stopped {
+		t.Error("expected sequence to be stopped")
+	}
+}
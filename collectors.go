@@ -0,0 +1,112 @@
+package kk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrDuplicateKey is reported by ToMap with DuplicateError when two items
+// have the same key. The reported error wraps it and includes the key.
+var ErrDuplicateKey = errors.New("duplicate key")
+
+// DuplicatePolicy controls what ToMap does when two items have the same key.
+type DuplicatePolicy int
+
+const (
+	// DuplicateError reports ErrDuplicateKey according to the chain's
+	// ErrorPolicy and keeps the first value. This is the default.
+	DuplicateError DuplicatePolicy = iota
+	// DuplicateFirst keeps the value of the first item with the key.
+	DuplicateFirst
+	// DuplicateLast keeps the value of the last item with the key.
+	DuplicateLast
+)
+
+// ToMap materializes the query to a map from keyFn to valFn of each item.
+// Duplicate keys are handled according to policy; use ToMapMerge to combine them.
+// Returns the map built so far and the error reported by the query chain, if any.
+// This is a function (not a method) because it returns a different type.
+func ToMap[T any, K comparable, V any](
+	q *KKQuery[T], keyFn func(T) K, valFn func(T) V, policy DuplicatePolicy,
+) (map[K]V, error) {
+	r := newRun(context.Background())
+	defer r.close()
+	iter := q.iterate(r)
+
+	result := make(map[K]V)
+	for !r.done() {
+		item, ok := iter()
+		if !ok {
+			break
+		}
+		key := keyFn(item)
+		if _, exists := result[key]; exists {
+			switch policy {
+			case DuplicateFirst:
+				continue
+			case DuplicateError:
+				r.fail(fmt.Errorf("%w %v", ErrDuplicateKey, key))
+				continue
+			}
+		}
+		result[key] = valFn(item)
+	}
+	return result, r.err()
+}
+
+// ToMapMerge is like ToMap but combines the values of items with the same key
+// by calling merge with the value so far and the new item's value.
+// This is a function (not a method) because it returns a different type.
+func ToMapMerge[T any, K comparable, V any](
+	q *KKQuery[T], keyFn func(T) K, valFn func(T) V, merge func(existing V, next V) V,
+) (map[K]V, error) {
+	r := newRun(context.Background())
+	defer r.close()
+	iter := q.iterate(r)
+
+	result := make(map[K]V)
+	for !r.done() {
+		item, ok := iter()
+		if !ok {
+			break
+		}
+		key := keyFn(item)
+		value := valFn(item)
+		if existing, exists := result[key]; exists {
+			value = merge(existing, value)
+		}
+		result[key] = value
+	}
+	return result, r.err()
+}
+
+// ToLookup materializes the query to a map from key to the items with that
+// key, in query order. Unlike GroupedBy, the result can be indexed directly.
+// Returns the map built so far and the error reported by the query chain, if any.
+// This is a function (not a method) because it returns a different type.
+func ToLookup[T any, K comparable](q *KKQuery[T], keyFn func(T) K) (map[K][]T, error) {
+	r := newRun(context.Background())
+	defer r.close()
+	lookup := buildLookup(r, q, keyFn)
+	return lookup, r.err()
+}
+
+// ToSet materializes the query to a set of its distinct items.
+// Returns the set built so far and the error reported by the query chain, if any.
+// This is a function (not a method) because it requires a comparable type.
+func ToSet[T comparable](q *KKQuery[T]) (map[T]struct{}, error) {
+	r := newRun(context.Background())
+	defer r.close()
+	iter := q.iterate(r)
+
+	result := make(map[T]struct{})
+	for !r.done() {
+		item, ok := iter()
+		if !ok {
+			break
+		}
+		result[item] = struct{}{}
+	}
+	return result, r.err()
+}
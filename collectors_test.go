@@ -0,0 +1,159 @@
+package kk
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+type mapItem struct {
+	Key   string
+	Value int
+}
+
+var mapItems = []mapItem{{"a", 1}, {"b", 2}, {"a", 3}}
+
+func mapKey(m mapItem) string { return m.Key }
+func mapValue(m mapItem) int  { return m.Value }
+
+func TestToMap(t *testing.T) {
+	result, err := ToMap(Query([]mapItem{{"a", 1}, {"b", 2}}), mapKey, mapValue, DuplicateError)
+
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if len(result) != 2 || result["a"] != 1 || result["b"] != 2 {
+		t.Errorf("expected map[a:1 b:2], got %v", result)
+	}
+}
+
+func TestToMapDuplicateError(t *testing.T) {
+	result, err := ToMap(Query(mapItems), mapKey, mapValue, DuplicateError)
+
+	if !errors.Is(err, ErrDuplicateKey) {
+		t.Errorf("expected ErrDuplicateKey, got %v", err)
+	}
+	if err == nil || err.Error() != "duplicate key a" {
+		t.Errorf("expected duplicate key a, got %v", err)
+	}
+	if result["a"] != 1 {
+		t.Errorf("expected first value 1 for a, got %d", result["a"])
+	}
+}
+
+func TestToMapDuplicateErrorCollect(t *testing.T) {
+	items := append(mapItems, mapItem{"b", 4}, mapItem{"c", 5})
+	result, err := ToMap(Query(items).OnError(CollectErrors), mapKey, mapValue, DuplicateError)
+
+	if err == nil || strings.Count(err.Error(), "duplicate key") != 2 {
+		t.Errorf("expected 2 duplicate key errors, got %v", err)
+	}
+	if len(result) != 3 || result["c"] != 5 {
+		t.Errorf("expected map[a:1 b:2 c:5], got %v", result)
+	}
+}
+
+func TestToMapDuplicateFirst(t *testing.T) {
+	result, err := ToMap(Query(mapItems), mapKey, mapValue, DuplicateFirst)
+
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if result["a"] != 1 {
+		t.Errorf("expected 1 for a, got %d", result["a"])
+	}
+}
+
+func TestToMapDuplicateLast(t *testing.T) {
+	result, err := ToMap(Query(mapItems), mapKey, mapValue, DuplicateLast)
+
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if result["a"] != 3 {
+		t.Errorf("expected 3 for a, got %d", result["a"])
+	}
+}
+
+func TestToMapChainError(t *testing.T) {
+	q := MappedErr(Query([]int{1, 2, 3}), failOn(2))
+	result, err := ToMap(q, func(n int) int { return n }, func(n int) int { return n * 10 }, DuplicateError)
+
+	if err == nil || err.Error() != "bad item 2" {
+		t.Errorf("expected bad item 2, got %v", err)
+	}
+	if len(result) != 1 || result[1] != 10 {
+		t.Errorf("expected map[1:10], got %v", result)
+	}
+}
+
+func TestToMapMerge(t *testing.T) {
+	result, err := ToMapMerge(Query(mapItems), mapKey, mapValue, func(a int, b int) int { return a + b })
+
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if result["a"] != 4 || result["b"] != 2 {
+		t.Errorf("expected map[a:4 b:2], got %v", result)
+	}
+}
+
+func TestToLookup(t *testing.T) {
+	result, err := ToLookup(Query(mapItems), mapKey)
+
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if len(result) != 2 {
+		t.Fatalf("expected 2 keys, got %d", len(result))
+	}
+	a := result["a"]
+	if len(a) != 2 || a[0].Value != 1 || a[1].Value != 3 {
+		t.Errorf("expected [{a 1} {a 3}], got %v", a)
+	}
+	if len(result["missing"]) != 0 {
+		t.Errorf("expected no items for a missing key, got %v", result["missing"])
+	}
+}
+
+func TestToSet(t *testing.T) {
+	result, err := ToSet(Query([]string{"x", "y", "x"}))
+
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if len(result) != 2 {
+		t.Errorf("expected 2 items, got %d", len(result))
+	}
+	if _, ok := result["y"]; !ok {
+		t.Error("expected y in set")
+	}
+}
+
+func TestToSetEmpty(t *testing.T) {
+	result, _ := ToSet(Query([]int{}))
+
+	if result == nil || len(result) != 0 {
+		t.Errorf("expected empty set, got %v", result)
+	}
+}
+
+func TestToLookupAndToSetErrors(t *testing.T) {
+	q := MappedErr(Query([]int{1, 2, 3}), failOn(2))
+
+	lookup, err := ToLookup(q, func(n int) bool { return n%2 == 0 })
+	if err == nil || err.Error() != "bad item 2" {
+		t.Errorf("ToLookup: expected bad item 2, got %v", err)
+	}
+	if len(lookup) != 1 || len(lookup[false]) != 1 {
+		t.Errorf("ToLookup: expected the item before the error, got %v", lookup)
+	}
+
+	set, err := ToSet(q)
+	if err == nil || err.Error() != "bad item 2" {
+		t.Errorf("ToSet: expected bad item 2, got %v", err)
+	}
+	if len(set) != 1 {
+		t.Errorf("ToSet: expected the item before the error, got %v", set)
+	}
+}
@@ -0,0 +1,363 @@
+package kk
+
+import (
+	"context"
+	"encoding"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CSVOptions configures QueryCSV and WriteCSV.
+type CSVOptions struct {
+	// Comma is the field delimiter. Defaults to ','.
+	Comma rune
+	// TimeLayout is the layout used for time.Time fields. Defaults to time.RFC3339.
+	TimeLayout string
+	// Malformed controls what happens to rows that fail to parse or convert:
+	// StopOnError ends iteration, CollectErrors skips the row and reports
+	// its error, and SkipErrors skips the row silently.
+	// Errors are reported as *CSVError.
+	Malformed ErrorPolicy
+}
+
+// CSVError reports an error at a data row of a CSV input.
+// Row is 1-based and does not count the header. Column is 1-based, or 0
+// when the error concerns the whole row.
+type CSVError struct {
+	Row    int
+	Column int
+	Header string
+	Err    error
+}
+
+func (e *CSVError) Error() string {
+	if e.Column == 0 {
+		return fmt.Sprintf("row %d: %v", e.Row, e.Err)
+	}
+	return fmt.Sprintf("row %d, column %d (%s): %v", e.Row, e.Column, e.Header, e.Err)
+}
+
+func (e *CSVError) Unwrap() error {
+	return e.Err
+}
+
+// QueryCSV creates a KKQuery that reads rows of rd into structs of type T.
+// The first row is the header. Columns map to exported fields by their
+// `csv:"name"` tag, or by field name ignoring case; unknown columns are
+// ignored and `csv:"-"` fields are skipped. Supported field types are
+// strings, integers, floats, bools, time.Time, time.Duration and
+// encoding.TextUnmarshaler. Empty cells leave the zero value.
+// Rows are read one at a time. If rd is an io.Closer it is closed when
+// iteration ends, including on early stop.
+func QueryCSV[T any](rd io.Reader, opts CSVOptions) *KKQuery[T] {
+	return &KKQuery[T]{
+		iterate: func(r *run) Iterator[T] {
+			if c, ok := rd.(io.Closer); ok {
+				r.onClose(func() { _ = c.Close() })
+			}
+			reader := csv.NewReader(rd)
+			if opts.Comma != 0 {
+				reader.Comma = opts.Comma
+			}
+			reader.ReuseRecord = true
+			layout := csvTimeLayout(opts)
+
+			var columns []csvColumn
+			started := false
+			row := 0
+			return func() (T, bool) {
+				var zero T
+				if !started {
+					started = true
+					var err error
+					if columns, err = readCSVHeader(reader, reflect.TypeOf(zero)); err != nil {
+						r.fail(err)
+						return zero, false
+					}
+				}
+				for !r.done() {
+					record, err := reader.Read()
+					if err == io.EOF {
+						return zero, false
+					}
+					row++
+					if err != nil {
+						var parseErr *csv.ParseError
+						if !errors.As(err, &parseErr) {
+							r.fail(err)
+							return zero, false
+						}
+						if r.failWith(opts.Malformed, &CSVError{Row: row, Err: err}) {
+							continue
+						}
+						return zero, false
+					}
+
+					var item T
+					if err := decodeCSVRow(reflect.ValueOf(&item).Elem(), columns, record, row, layout); err != nil {
+						if r.failWith(opts.Malformed, err) {
+							continue
+						}
+						return zero, false
+					}
+					return item, true
+				}
+				return zero, false
+			}
+		},
+	}
+}
+
+// WriteCSV writes a header and one row per item of the query to w.
+// Columns follow the field mapping of QueryCSV, in field order.
+// Each row is flushed to w as soon as it is written, so rows written before
+// an error are not lost. Returns the first formatting or write error, or the
+// error reported by the query chain.
+func WriteCSV[T any](w io.Writer, q *KKQuery[T], opts CSVOptions) error {
+	var zero T
+	fields, err := csvFields(reflect.TypeOf(zero))
+	if err != nil {
+		return err
+	}
+	layout := csvTimeLayout(opts)
+
+	writer := csv.NewWriter(w)
+	if opts.Comma != 0 {
+		writer.Comma = opts.Comma
+	}
+
+	writeRow := func(record []string) error {
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+		writer.Flush()
+		return writer.Error()
+	}
+
+	record := make([]string, len(fields))
+	for i, f := range fields {
+		record[i] = f.name
+	}
+	if err := writeRow(record); err != nil {
+		return err
+	}
+
+	r := newRun(context.Background())
+	defer r.close()
+
+	iter := q.iterate(r)
+	for {
+		item, ok := iter()
+		if !ok {
+			break
+		}
+		v := reflect.ValueOf(item)
+		for i, f := range fields {
+			if record[i], err = formatCSVValue(v.FieldByIndex(f.index), layout); err != nil {
+				return fmt.Errorf("field %s: %w", f.name, err)
+			}
+		}
+		if err := writeRow(record); err != nil {
+			return err
+		}
+	}
+	return r.err()
+}
+
+// csvField is a struct field that maps to a CSV column.
+type csvField struct {
+	name  string
+	index []int
+}
+
+// csvColumn maps a column of the input to a struct field.
+type csvColumn struct {
+	header string
+	field  *csvField
+}
+
+var (
+	timeType            = reflect.TypeOf(time.Time{})
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// csvFields returns the mapped fields of struct type t.
+func csvFields(t reflect.Type) ([]csvField, error) {
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("kk: CSV requires a struct type, got %v", t)
+	}
+	var fields []csvField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name := f.Tag.Get("csv")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		if !csvSupported(f.Type) {
+			return nil, fmt.Errorf("kk: unsupported CSV field type %v for field %s", f.Type, f.Name)
+		}
+		fields = append(fields, csvField{name: name, index: f.Index})
+	}
+	return fields, nil
+}
+
+// csvSupported reports whether values of type t can be converted to and from CSV.
+func csvSupported(t reflect.Type) bool {
+	if t == timeType || reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// readCSVHeader reads the header row and maps its columns to the fields of t.
+func readCSVHeader(reader *csv.Reader, t reflect.Type) ([]csvColumn, error) {
+	fields, err := csvFields(t)
+	if err != nil {
+		return nil, err
+	}
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	// Spreadsheet exports often start with a UTF-8 byte order mark
+	header[0] = strings.TrimPrefix(header[0], "\ufeff")
+
+	columns := make([]csvColumn, len(header))
+	for i, name := range header {
+		columns[i].header = name
+		for j := range fields {
+			if fields[j].name == name {
+				columns[i].field = &fields[j]
+				break
+			}
+			if columns[i].field == nil && strings.EqualFold(fields[j].name, name) {
+				columns[i].field = &fields[j]
+			}
+		}
+	}
+	return columns, nil
+}
+
+// decodeCSVRow sets the fields of v from a record.
+func decodeCSVRow(v reflect.Value, columns []csvColumn, record []string, row int, layout string) error {
+	for i, cell := range record {
+		if i >= len(columns) || columns[i].field == nil || cell == "" {
+			continue
+		}
+		if err := parseCSVValue(v.FieldByIndex(columns[i].field.index), cell, layout); err != nil {
+			return &CSVError{Row: row, Column: i + 1, Header: columns[i].header, Err: err}
+		}
+	}
+	return nil
+}
+
+// parseCSVValue converts s and stores it in v.
+func parseCSVValue(v reflect.Value, s string, layout string) error {
+	switch {
+	case v.Type() == timeType:
+		t, err := time.Parse(layout, s)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(t))
+		return nil
+	case v.Type() == durationType:
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	case v.Addr().Type().Implements(textUnmarshalerType):
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	}
+	return nil
+}
+
+// formatCSVValue converts v to its CSV representation.
+func formatCSVValue(v reflect.Value, layout string) (string, error) {
+	switch {
+	case v.Type() == timeType:
+		return v.Interface().(time.Time).Format(layout), nil
+	case v.Type() == durationType:
+		return time.Duration(v.Int()).String(), nil
+	case v.Type().Implements(textMarshalerType):
+		b, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		return string(b), err
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()), nil
+	}
+	return fmt.Sprint(v.Interface()), nil
+}
+
+// csvTimeLayout returns the time layout configured in opts.
+func csvTimeLayout(opts CSVOptions) string {
+	if opts.TimeLayout != "" {
+		return opts.TimeLayout
+	}
+	return time.RFC3339
+}
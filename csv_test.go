@@ -0,0 +1,216 @@
+package kk
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+type csvRecord struct {
+	ID       int           `csv:"id"`
+	Name     string        `csv:"name"`
+	Score    float64       `csv:"score"`
+	Active   bool          `csv:"active"`
+	Joined   time.Time     `csv:"joined"`
+	Timeout  time.Duration `csv:"timeout"`
+	Internal string        `csv:"-"`
+	Country  string
+}
+
+func TestQueryCSV(t *testing.T) {
+	input := "id,name,score,active,joined,timeout,country,extra\n" +
+		"1,Alice,9.5,true,2024-01-02T03:04:05Z,1m30s,US,x\n" +
+		"2,Bob,,false,,,UK,y\n"
+	result, err := SliceErr(QueryCSV[csvRecord](strings.NewReader(input), CSVOptions{}))
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(result) != 2 {
+		t.Fatalf("expected length 2, got %d", len(result))
+	}
+	alice := result[0]
+	if alice.ID != 1 || alice.Name != "Alice" || alice.Score != 9.5 || !alice.Active {
+		t.Errorf("unexpected record %+v", alice)
+	}
+	if !alice.Joined.Equal(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)) {
+		t.Errorf("expected joined time, got %v", alice.Joined)
+	}
+	if alice.Timeout != 90*time.Second {
+		t.Errorf("expected timeout 1m30s, got %v", alice.Timeout)
+	}
+	if alice.Country != "US" {
+		t.Errorf("expected country matched by field name, got %q", alice.Country)
+	}
+	if result[1].Score != 0 || !result[1].Joined.IsZero() {
+		t.Errorf("expected empty cells to leave zero values, got %+v", result[1])
+	}
+}
+
+func TestQueryCSVConversionError(t *testing.T) {
+	input := "id,name\n1,a\nx,b\n3,c\n"
+	result, err := SliceErr(QueryCSV[csvRecord](strings.NewReader(input), CSVOptions{}))
+
+	var csvErr *CSVError
+	if !errors.As(err, &csvErr) {
+		t.Fatalf("expected *CSVError, got %v", err)
+	}
+	if csvErr.Row != 2 || csvErr.Column != 1 || csvErr.Header != "id" {
+		t.Errorf("expected row 2, column 1 (id), got %v", csvErr)
+	}
+	if len(result) != 1 {
+		t.Errorf("expected length 1, got %d", len(result))
+	}
+}
+
+func TestQueryCSVMalformedCollect(t *testing.T) {
+	input := "id,active\n1,true\n2,maybe\n3\n4,false\n"
+	q := QueryCSV[csvRecord](strings.NewReader(input), CSVOptions{Malformed: CollectErrors})
+	result, err := SliceErr(q)
+
+	if len(result) != 2 || result[1].ID != 4 {
+		t.Errorf("expected rows 1 and 4, got %+v", result)
+	}
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if !strings.Contains(err.Error(), "row 2, column 2 (active)") || !strings.Contains(err.Error(), "row 3:") {
+		t.Errorf("expected errors for rows 2 and 3, got %v", err)
+	}
+}
+
+func TestQueryCSVMalformedSkip(t *testing.T) {
+	input := "id\n1\nx\n3\n"
+	result, err := SliceErr(QueryCSV[csvRecord](strings.NewReader(input), CSVOptions{Malformed: SkipErrors}))
+
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if len(result) != 2 {
+		t.Errorf("expected length 2, got %d", len(result))
+	}
+}
+
+func TestQueryCSVByteOrderMark(t *testing.T) {
+	type person struct {
+		Name string `csv:"name"`
+		Age  int    `csv:"age"`
+	}
+	result, err := SliceErr(QueryCSV[person](strings.NewReader("\ufeffname,age\nbob,3\n"), CSVOptions{}))
+
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if len(result) != 1 || result[0].Name != "bob" || result[0].Age != 3 {
+		t.Errorf("expected [{bob 3}], got %v", result)
+	}
+}
+
+func TestQueryCSVNotStruct(t *testing.T) {
+	_, err := SliceErr(QueryCSV[int](strings.NewReader("a\n1\n"), CSVOptions{}))
+
+	if err == nil {
+		t.Error("expected error, got nil")
+	}
+}
+
+func TestQueryCSVEarlyStop(t *testing.T) {
+	rd := &closeTracker{Reader: strings.NewReader("id\n1\n2\n3\n")}
+	result := Slice(QueryCSV[csvRecord](rd, CSVOptions{}).Take(1))
+
+	if len(result) != 1 {
+		t.Errorf("expected length 1, got %d", len(result))
+	}
+	if rd.closed != 1 {
+		t.Errorf("expected reader closed once, got %d", rd.closed)
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	records := []csvRecord{
+		{ID: 1, Name: "Alice", Score: 9.5, Active: true, Joined: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), Country: "US"},
+		{ID: 2, Name: "Bob, Jr.", Timeout: time.Second},
+	}
+	var buf bytes.Buffer
+
+	if err := WriteCSV(&buf, Query(records), CSVOptions{TimeLayout: time.DateOnly}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expected := "id,name,score,active,joined,timeout,Country\n" +
+		"1,Alice,9.5,true,2024-01-02,0s,US\n" +
+		"2,\"Bob, Jr.\",0,false,0001-01-01,1s,\n"
+	if buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+
+	// Round trip
+	result := Slice(QueryCSV[csvRecord](&buf, CSVOptions{TimeLayout: time.DateOnly}))
+	if len(result) != 2 || result[0] != records[0] || result[1].Name != "Bob, Jr." {
+		t.Errorf("expected %+v, got %+v", records, result)
+	}
+}
+
+// csvCode fails to marshal when it is negative.
+type csvCode int
+
+func (c csvCode) MarshalText() ([]byte, error) {
+	if c < 0 {
+		return nil, errors.New("negative code")
+	}
+	return []byte(strconv.Itoa(int(c))), nil
+}
+
+func TestWriteCSVKeepsRowsBeforeError(t *testing.T) {
+	type row struct {
+		Code csvCode `csv:"code"`
+	}
+	var buf bytes.Buffer
+	err := WriteCSV(&buf, Query([]row{{1}, {2}, {-1}, {3}}), CSVOptions{})
+
+	if err == nil || err.Error() != "field code: negative code" {
+		t.Errorf("expected field code: negative code, got %v", err)
+	}
+	if buf.String() != "code\n1\n2\n" {
+		t.Errorf("expected the rows before the error to be flushed, got %q", buf.String())
+	}
+}
+
+func TestWriteCSVFlushesEachRow(t *testing.T) {
+	type row struct {
+		N int `csv:"n"`
+	}
+	var buf bytes.Buffer
+	var seen []string
+	q := Mapped(
+		Query([]int{1, 2}), func(n int) row {
+			// Everything written so far is visible before the next item is produced
+			seen = append(seen, buf.String())
+			return row{n}
+		},
+	)
+
+	if err := WriteCSV(&buf, q, CSVOptions{}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(seen) != 2 || seen[0] != "n\n" || seen[1] != "n\n1\n" {
+		t.Errorf("expected each row flushed as written, got %q", seen)
+	}
+}
+
+func TestWriteCSVDelimiter(t *testing.T) {
+	type row struct {
+		A string `csv:"a"`
+		B int    `csv:"b"`
+	}
+	var buf bytes.Buffer
+
+	if err := WriteCSV(&buf, Query([]row{{"x", 1}}), CSVOptions{Comma: ';'}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if buf.String() != "a;b\nx;1\n" {
+		t.Errorf("expected semicolon separated output, got %q", buf.String())
+	}
+}
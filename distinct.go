@@ -3,8 +3,8 @@ package kk
 // Distinct removes duplicate items (requires comparable type).
 func (q *KKQuery[T]) Distinct() *KKQuery[T] {
 	return &KKQuery[T]{
-		iterate: func() Iterator[T] {
-			iter := q.iterate()
+		iterate: func(r *run) Iterator[T] {
+			iter := q.iterate(r)
 			seen := make(map[any]bool)
 			return func() (T, bool) {
 				for {
@@ -26,8 +26,8 @@ func (q *KKQuery[T]) Distinct() *KKQuery[T] {
 // DistinctBy removes duplicate items based on a key function.
 func DistinctBy[T any, K comparable](q *KKQuery[T], keyFn func(T) K) *KKQuery[T] {
 	return &KKQuery[T]{
-		iterate: func() Iterator[T] {
-			iter := q.iterate()
+		iterate: func(r *run) Iterator[T] {
+			iter := q.iterate(r)
 			seen := make(map[K]bool)
 			return func() (T, bool) {
 				for {
@@ -15,6 +15,7 @@
 //
 // Methods that return a new KKQuery of the same type:
 //   - Where(predicate) - Filter items
+//   - WhereErr(predicate) - Filter items with a predicate that can fail
 //   - Take(n) - First n items
 //   - Skip(n) - Skip first n items
 //   - TakeWhile(predicate) - Take while condition is true
@@ -24,15 +25,66 @@
 //   - Except(other) - Items not in other
 //   - Intersect(other) - Items in both
 //   - Union(other) - Items in either (distinct)
+//   - Cached() - Evaluate upstream once, replay afterwards; returns a CachedQuery
+//   - CachedLazy() - Cache items incrementally as they are pulled; returns a CachedQuery
+//   - Close() - Stop and release the upstream of a CachedQuery
+//   - All() - Iterate as iter.Seq
+//   - Enumerate() - Iterate as iter.Seq2 of index and item
+//   - AllErr() - Iterate as iter.Seq2 of item and error, yielding the chain's error last
+//   - AllCtx(ctx) - Like AllErr, cancellable
+//   - OnError(policy) - Stop on first error or skip and collect errors
+//   - OnClose(fn) - Run cleanup once when iteration ends
 //
 // # Functions (terminal)
 //
 // Package-level functions that transform, execute, or aggregate:
 //   - Query(slice) - Create query from slice
 //   - QueryChan(ch) - Create query from channel
+//   - QueryMapKeys(m) - Create query from map keys
+//   - QueryMapValues(m) - Create query from map values
+//   - QueryMapEntries(m) - Create query of KeyValue pairs from a map
+//   - QueryMapKeysSorted(m) - Map keys in ascending order
+//   - QueryMapValuesSorted(m) - Map values ordered by key
+//   - QueryMapEntriesSorted(m) - Map entries ordered by key
+//   - Range(start, end, step) - Numbers from start up to end
+//   - Repeat(v, n) - Repeat a value n times
+//   - Generate(fn) - Infinite query of fn(i)
+//   - Unfold(seed, fn) - Items produced from an evolving state
+//   - QuerySeq(seq) - Create query from iter.Seq
+//   - QuerySeq2(seq) - Create query of KeyValue from iter.Seq2
+//   - QueryLines(r) - Create query from lines of an io.Reader
+//   - QueryReader(r, opts) - Create query from tokens of an io.Reader
+//   - QueryJSONL[T](r, opts) - Decode JSON Lines into T
+//   - QueryCSV[T](r, opts) - Read CSV rows into structs by header
+//   - QueryFS(fsys, root, opts) - Lazily walk an fs.FS
+//   - QueryDir(dir, opts) - Lazily walk a directory
+//   - QueryRows(rows, scan) - Lazily scan *sql.Rows
+//   - QuerySQL(db, scan, query, args...) - Run a SQL query per iteration and scan lazily
+//   - QueryPages(start, fetch, opts) - Lazily flatten a paginated API
+//   - QueryTicker(interval, opts) - Emit the time every interval
+//   - QueryPoll(interval, poll, opts) - Call poll every interval and yield its items
 //   - Map(q, fn) - Transform each item to new type
+//   - MappedErr(q, fn) - Transform with a function that can fail
+//   - Merge(queries...) - Iterate queries concurrently, in arrival order
+//   - MergeChan(chs...) - Merge channels in arrival order
+//   - Zip(a, b) - Pair items by position, up to the shorter query
+//   - ZipWith(a, b, fn) - Combine items by position with fn
+//   - ZipLongest(a, b) - Pair items by position, zero-filling the shorter query
+//   - Join(outer, inner, outerKey, innerKey, fn) - Hash join on equal keys
+//   - LeftJoin(outer, inner, outerKey, innerKey, fn) - Hash join keeping unmatched outer items
+//   - GroupJoin(outer, inner, outerKey, innerKey, fn) - Pair each outer item with its matching inner items
+//   - MergeJoin(outer, inner, outerKey, innerKey, fn) - Streaming join of inputs sorted by key
 //   - FlatMap(q, fn) - Transform and flatten
+//   - FlattenedQuery(q, fn) - Transform to queries and flatten
+//   - Scan(q, seed, fn) - Yield the running accumulator after each item
 //   - Chunk(q, size) - Split into batches
+//   - GroupAdjacent(q, keyFn) - Stream groups of consecutive items with the same key
+//   - Window(q, size, step) - Sliding, tumbling or hopping windows by count
+//   - WindowByTime(q, ts, opts) - Tumbling or sliding windows by timestamp
+//   - SessionWindow(q, keyFn, ts, gap) - Per-key sessions that close after gap of inactivity
+//   - CountBy(q, keyFn) - Count items per key
+//   - SumBy(q, keyFn, fn) - Sum values per key
+//   - AggregateBy(q, keyFn, seed, fn) - Fold items per key
 //   - DistinctBy(q, keyFn) - Remove duplicates by key
 //   - OrderBy(q, keyFn) - Sort ascending
 //   - OrderByDescending(q, keyFn) - Sort descending
@@ -43,12 +95,33 @@
 //   - ParallelByKey(q, ctx, n, perKey, keyFn, fn) - Parallel with per-key limit
 //   - ParallelByBatch(q, ctx, size, n, fn) - Process in batches
 //   - Count(q) - Count items
+//   - CountErr(q) - Count items and return the chain's error
+//   - CountCtx(ctx, q) - Count items, cancellable
 //   - Sum(q, fn) - Sum values
+//   - Min(q) - Smallest item
+//   - Max(q) - Largest item
+//   - MinBy(q, keyFn) - Item with the smallest key
+//   - MaxBy(q, keyFn) - Item with the largest key
+//   - Average(q, fn) - Mean of values
+//   - Reduce(q, fn) - Combine items, starting with the first
+//   - Fold(q, seed, fn) - Combine items into an accumulator
+//   - Summarize(q, fn) - Count, sum, min, max and mean in one pass
+//   - MinErr(q), MinCtx(ctx, q), ... - Err and Ctx forms of Min through Summarize return the chain's error
 //   - First(q) - First item
 //   - Any(q, predicate) - Any match?
 //   - All(q, predicate) - All match?
 //   - ToSlice(q) - Materialize to slice
+//   - SliceErr(q) - Materialize and return the chain's error
+//   - SliceCtx(ctx, q) - Materialize, cancellable
+//   - ToMap(q, keyFn, valFn, policy) - Materialize to a map, with a duplicate key policy
+//   - ToMapMerge(q, keyFn, valFn, merge) - Materialize to a map, merging duplicate keys
+//   - ToLookup(q, keyFn) - Materialize to map[K][]T
+//   - ToSet(q) - Materialize to map[T]struct{}
 //   - Print(q) - Print items (debug)
+//   - ToChan(ctx, q, buffer) - Produce items to a channel from a goroutine
+//   - ToChanErr(ctx, q, buffer) - Produce to a channel and report the chain's error
+//   - WriteJSONL(w, q) - Encode items as JSON Lines
+//   - WriteCSV(w, q, opts) - Write items as CSV with a header
 //
 // # Parallel Execution
 //
@@ -0,0 +1,44 @@
+package kk
+
+import "fmt"
+
+// ErrorPolicy controls what happens when a stage of a query chain fails.
+type ErrorPolicy int
+
+const (
+	// StopOnError ends iteration at the first error. This is the default.
+	StopOnError ErrorPolicy = iota
+	// CollectErrors skips the failing item, keeps iterating, and reports
+	// all errors together when the terminal operation returns.
+	CollectErrors
+	// SkipErrors skips the failing item and discards the error.
+	SkipErrors
+)
+
+// OnError sets the error policy for the whole query chain.
+// If OnError is applied more than once, the outermost call wins.
+func (q *KKQuery[T]) OnError(policy ErrorPolicy) *KKQuery[T] {
+	return &KKQuery[T]{
+		iterate: func(r *run) Iterator[T] {
+			if !r.policySet {
+				r.policy = policy
+				r.policySet = true
+			}
+			return q.iterate(r)
+		},
+	}
+}
+
+// LineError reports an error at a line of a line-oriented input.
+type LineError struct {
+	Line int
+	Err  error
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *LineError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,132 @@
+package kk
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func failOn(bad int) func(int) (int, error) {
+	return func(n int) (int, error) {
+		if n == bad {
+			return 0, fmt.Errorf("bad item %d", n)
+		}
+		return n, nil
+	}
+}
+
+func TestOnErrorStop(t *testing.T) {
+	q := MappedErr(Query([]int{1, 2, 3, 4}), failOn(2)).OnError(StopOnError)
+	result, err := SliceErr(q)
+
+	if err == nil || err.Error() != "bad item 2" {
+		t.Errorf("expected bad item 2, got %v", err)
+	}
+	if len(result) != 1 {
+		t.Errorf("expected length 1, got %d", len(result))
+	}
+}
+
+func TestOnErrorCollect(t *testing.T) {
+	q := MappedErr(MappedErr(Query([]int{1, 2, 3, 4}), failOn(2)), failOn(4)).OnError(CollectErrors)
+	result, err := SliceErr(q)
+
+	if len(result) != 2 || result[0] != 1 || result[1] != 3 {
+		t.Errorf("expected [1 3], got %v", result)
+	}
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if err.Error() != "bad item 2\nbad item 4" {
+		t.Errorf("expected both errors, got %q", err.Error())
+	}
+}
+
+func TestOnErrorOutermostWins(t *testing.T) {
+	q := MappedErr(Query([]int{1, 2, 3}), failOn(2)).OnError(StopOnError).OnError(CollectErrors)
+	result, err := SliceErr(q)
+
+	if err == nil {
+		t.Error("expected error, got nil")
+	}
+	if len(result) != 2 {
+		t.Errorf("expected length 2, got %d", len(result))
+	}
+}
+
+func TestErrorStopsConcat(t *testing.T) {
+	var pulled bool
+	second := Mapped(
+		Query([]int{10}), func(n int) int {
+			pulled = true
+			return n
+		},
+	)
+	q := MappedErr(Query([]int{1, 2}), failOn(2)).Concat(second)
+	result, err := SliceErr(q)
+
+	if err == nil {
+		t.Error("expected error, got nil")
+	}
+	if len(result) != 1 {
+		t.Errorf("expected length 1, got %d", len(result))
+	}
+	if pulled {
+		t.Error("expected second query not to be pulled after error")
+	}
+}
+
+func TestErrorStopsExcept(t *testing.T) {
+	other := MappedErr(Query([]int{1, 2}), failOn(2))
+	result, err := SliceErr(Query([]int{1, 3, 5}).Except(other))
+
+	if err == nil {
+		t.Error("expected error, got nil")
+	}
+	if len(result) != 0 {
+		t.Errorf("expected empty slice, got %v", result)
+	}
+}
+
+func TestErrorThroughSortedBy(t *testing.T) {
+	expectedErr := errors.New("test error")
+	q := MappedErr(
+		Query([]int{3, 1, 2}), func(n int) (int, error) {
+			if n == 2 {
+				return 0, expectedErr
+			}
+			return n, nil
+		},
+	)
+	_, err := SliceErr(SortedBy(q, func(n int) int { return n }).KKQuery)
+
+	if err != expectedErr {
+		t.Errorf("expected %v, got %v", expectedErr, err)
+	}
+}
+
+func TestErrorReplayedByCached(t *testing.T) {
+	q := MappedErr(Query([]int{1, 2, 3}), failOn(3)).Cached()
+
+	for i := 0; i < 2; i++ {
+		result, err := SliceErr(q.KKQuery)
+		if err == nil {
+			t.Errorf("iteration %d: expected error, got nil", i)
+		}
+		if len(result) != 2 {
+			t.Errorf("iteration %d: expected length 2, got %d", i, len(result))
+		}
+	}
+}
+
+func TestOnErrorSkip(t *testing.T) {
+	q := MappedErr(Query([]int{1, 2, 3}), failOn(2)).OnError(SkipErrors)
+	result, err := SliceErr(q)
+
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if len(result) != 2 || result[0] != 1 || result[1] != 3 {
+		t.Errorf("expected [1 3], got %v", result)
+	}
+}
@@ -3,8 +3,8 @@ package kk
 // Where filters items based on a predicate.
 func (q *KKQuery[T]) Where(predicate func(T) bool) *KKQuery[T] {
 	return &KKQuery[T]{
-		iterate: func() Iterator[T] {
-			iter := q.iterate()
+		iterate: func(r *run) Iterator[T] {
+			iter := q.iterate(r)
 			return func() (T, bool) {
 				for {
 					item, ok := iter()
@@ -21,11 +21,41 @@ func (q *KKQuery[T]) Where(predicate func(T) bool) *KKQuery[T] {
 	}
 }
 
+// WhereErr filters items based on a predicate that can fail.
+// Errors are reported to the terminal operation according to the chain's ErrorPolicy.
+func (q *KKQuery[T]) WhereErr(predicate func(T) (bool, error)) *KKQuery[T] {
+	return &KKQuery[T]{
+		iterate: func(r *run) Iterator[T] {
+			iter := q.iterate(r)
+			return func() (T, bool) {
+				for {
+					item, ok := iter()
+					if !ok {
+						var zero T
+						return zero, false
+					}
+					keep, err := predicate(item)
+					if err != nil {
+						if r.fail(err) {
+							continue
+						}
+						var zero T
+						return zero, false
+					}
+					if keep {
+						return item, true
+					}
+				}
+			}
+		},
+	}
+}
+
 // Take returns the first n items.
 func (q *KKQuery[T]) Take(n int) *KKQuery[T] {
 	return &KKQuery[T]{
-		iterate: func() Iterator[T] {
-			iter := q.iterate()
+		iterate: func(r *run) Iterator[T] {
+			iter := q.iterate(r)
 			count := 0
 			return func() (T, bool) {
 				if count >= n {
@@ -47,8 +77,8 @@ func (q *KKQuery[T]) Take(n int) *KKQuery[T] {
 // Skip skips the first n items.
 func (q *KKQuery[T]) Skip(n int) *KKQuery[T] {
 	return &KKQuery[T]{
-		iterate: func() Iterator[T] {
-			iter := q.iterate()
+		iterate: func(r *run) Iterator[T] {
+			iter := q.iterate(r)
 			skipped := false
 			return func() (T, bool) {
 				if !skipped {
@@ -70,8 +100,8 @@ func (q *KKQuery[T]) Skip(n int) *KKQuery[T] {
 // TakeWhile returns items while the predicate is true.
 func (q *KKQuery[T]) TakeWhile(predicate func(T) bool) *KKQuery[T] {
 	return &KKQuery[T]{
-		iterate: func() Iterator[T] {
-			iter := q.iterate()
+		iterate: func(r *run) Iterator[T] {
+			iter := q.iterate(r)
 			done := false
 			return func() (T, bool) {
 				if done {
@@ -97,8 +127,8 @@ func (q *KKQuery[T]) TakeWhile(predicate func(T) bool) *KKQuery[T] {
 // SkipWhile skips items while the predicate is true.
 func (q *KKQuery[T]) SkipWhile(predicate func(T) bool) *KKQuery[T] {
 	return &KKQuery[T]{
-		iterate: func() Iterator[T] {
-			iter := q.iterate()
+		iterate: func(r *run) Iterator[T] {
+			iter := q.iterate(r)
 			skipping := true
 			return func() (T, bool) {
 				for {
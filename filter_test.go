@@ -1,6 +1,8 @@
 package kk
 
 import (
+	"errors"
+	"fmt"
 	"testing"
 )
 
@@ -208,3 +210,44 @@ func TestSkipWhileAllMatch(t *testing.T) {
 		t.Errorf("expected empty slice, got %v", result)
 	}
 }
+
+func TestWhereErr(t *testing.T) {
+	input := []int{1, 2, 3, 4, 5}
+	expectedErr := errors.New("test error")
+	q := Query(input).WhereErr(
+		func(n int) (bool, error) {
+			if n == 4 {
+				return false, expectedErr
+			}
+			return n%2 == 1, nil
+		},
+	)
+	result, err := SliceErr(q)
+
+	if err != expectedErr {
+		t.Errorf("expected %v, got %v", expectedErr, err)
+	}
+	if len(result) != 2 || result[0] != 1 || result[1] != 3 {
+		t.Errorf("expected [1 3], got %v", result)
+	}
+}
+
+func TestWhereErrCollect(t *testing.T) {
+	input := []int{1, 2, 3, 4, 5}
+	q := Query(input).WhereErr(
+		func(n int) (bool, error) {
+			if n%2 == 0 {
+				return false, fmt.Errorf("even: %d", n)
+			}
+			return true, nil
+		},
+	).OnError(CollectErrors)
+	result, err := SliceErr(q)
+
+	if err == nil || err.Error() != "even: 2\neven: 4" {
+		t.Errorf("expected joined errors, got %v", err)
+	}
+	if len(result) != 3 {
+		t.Errorf("expected length 3, got %d", len(result))
+	}
+}
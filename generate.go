@@ -0,0 +1,94 @@
+package kk
+
+// Range creates a KKQuery of numbers from start up to, but not including, end,
+// advancing by step. A negative step counts down. A zero step yields nothing.
+func Range[N Number](start, end, step N) *KKQuery[N] {
+	return &KKQuery[N]{
+		iterate: func(r *run) Iterator[N] {
+			var zero N
+			done := step == zero || (step > zero && start >= end) || (step < zero && start <= end)
+			// Integer division by two is zero only for integer types
+			floating := N(1)/N(2) != zero
+			value := start
+			i := 0
+			return func() (N, bool) {
+				if done {
+					return zero, false
+				}
+				current := value
+				i++
+				if floating {
+					// Compute from the index so float steps do not accumulate error
+					value = start + N(i)*step
+				} else {
+					value += step
+				}
+				// An integer step that wrapped around N has gone past end
+				if step > zero {
+					done = value >= end || value < current
+				} else {
+					done = value <= end || value > current
+				}
+				return current, true
+			}
+		},
+	}
+}
+
+// Repeat creates a KKQuery that yields v n times.
+func Repeat[T any](v T, n int) *KKQuery[T] {
+	return &KKQuery[T]{
+		iterate: func(r *run) Iterator[T] {
+			count := 0
+			return func() (T, bool) {
+				if count >= n {
+					var zero T
+					return zero, false
+				}
+				count++
+				return v, true
+			}
+		},
+	}
+}
+
+// Generate creates an infinite KKQuery whose i-th item is fn(i), starting at 0.
+// Combine it with Take or TakeWhile to bound it.
+func Generate[T any](fn func(i int) T) *KKQuery[T] {
+	return &KKQuery[T]{
+		iterate: func(r *run) Iterator[T] {
+			i := 0
+			return func() (T, bool) {
+				item := fn(i)
+				i++
+				return item, true
+			}
+		},
+	}
+}
+
+// Unfold creates a KKQuery by repeatedly applying fn to a state, starting
+// with seed. fn returns the next item, the next state, and false to end
+// the sequence. Each iteration starts again from seed.
+func Unfold[S any, T any](seed S, fn func(S) (T, S, bool)) *KKQuery[T] {
+	return &KKQuery[T]{
+		iterate: func(r *run) Iterator[T] {
+			state := seed
+			done := false
+			return func() (T, bool) {
+				if done {
+					var zero T
+					return zero, false
+				}
+				item, next, ok := fn(state)
+				if !ok {
+					done = true
+					var zero T
+					return zero, false
+				}
+				state = next
+				return item, true
+			}
+		},
+	}
+}
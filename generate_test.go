@@ -0,0 +1,140 @@
+package kk
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestRange(t *testing.T) {
+	tests := []struct {
+		start, end, step int
+		expected         []int
+	}{
+		{0, 5, 1, []int{0, 1, 2, 3, 4}},
+		{0, 10, 3, []int{0, 3, 6, 9}},
+		{5, 0, -2, []int{5, 3, 1}},
+		{0, 0, 1, nil},
+		{0, 5, 0, nil},
+		{5, 0, 1, nil},
+	}
+	for _, tt := range tests {
+		result := Slice(Range(tt.start, tt.end, tt.step))
+		if !slices.Equal(result, tt.expected) {
+			t.Errorf("Range(%d, %d, %d): expected %v, got %v", tt.start, tt.end, tt.step, tt.expected, result)
+		}
+	}
+}
+
+func TestRangeFloat(t *testing.T) {
+	result := Slice(Range(0.0, 1.0, 0.1))
+
+	if len(result) != 10 {
+		t.Fatalf("expected length 10, got %d: %v", len(result), result)
+	}
+	if result[3] != 0.30000000000000004 && result[3] != 0.3 {
+		t.Errorf("expected about 0.3, got %v", result[3])
+	}
+}
+
+func TestRangeUnsigned(t *testing.T) {
+	result := Slice(Range[uint8](250, 255, 2))
+
+	if !slices.Equal(result, []uint8{250, 252, 254}) {
+		t.Errorf("expected [250 252 254], got %v", result)
+	}
+}
+
+func TestRangeSmallTypes(t *testing.T) {
+	if n := Count(Range[int8](-100, 100, 1)); n != 200 {
+		t.Errorf("expected 200 int8 values, got %d", n)
+	}
+	if result := Slice(Range[int8](-128, 127, 50)); !slices.Equal(result, []int8{-128, -78, -28, 22, 72, 122}) {
+		t.Errorf("expected [-128 -78 -28 22 72 122], got %v", result)
+	}
+	if result := Slice(Range[int8](127, -128, -100)); !slices.Equal(result, []int8{127, 27, -73}) {
+		t.Errorf("expected [127 27 -73], got %v", result)
+	}
+	if n := Count(Range[uint8](0, 255, 1)); n != 255 {
+		t.Errorf("expected 255 uint8 values, got %d", n)
+	}
+	if result := Slice(Range[uint16](65530, 65535, 4)); !slices.Equal(result, []uint16{65530, 65534}) {
+		t.Errorf("expected [65530 65534], got %v", result)
+	}
+}
+
+func TestRangeWideSpan(t *testing.T) {
+	q := Range[int32](-2e9, 2e9, 1e9)
+	if result := Slice(q); !slices.Equal(result, []int32{-2e9, -1e9, 0, 1e9}) {
+		t.Errorf("expected [-2e9 -1e9 0 1e9], got %v", result)
+	}
+	if n := Count(Range[int16](-30000, 30000, 1)); n != 60000 {
+		t.Errorf("expected 60000 int16 values, got %d", n)
+	}
+	if result := Slice(Range[int32](2e9, -2e9, -1.5e9)); !slices.Equal(result, []int32{2e9, 5e8, -1e9}) {
+		t.Errorf("expected [2e9 5e8 -1e9], got %v", result)
+	}
+}
+
+func TestRepeat(t *testing.T) {
+	result := Slice(Repeat("x", 3))
+
+	if !slices.Equal(result, []string{"x", "x", "x"}) {
+		t.Errorf("expected [x x x], got %v", result)
+	}
+	if Count(Repeat(1, 0)) != 0 {
+		t.Error("expected empty query")
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	// Exponential backoff schedule
+	q := Generate(func(i int) time.Duration { return time.Millisecond << i })
+	result := Slice(q.TakeWhile(func(d time.Duration) bool { return d <= 8*time.Millisecond }))
+
+	expected := []time.Duration{time.Millisecond, 2 * time.Millisecond, 4 * time.Millisecond, 8 * time.Millisecond}
+	if !slices.Equal(result, expected) {
+		t.Errorf("expected %v, got %v", expected, result)
+	}
+
+	// Re-iterating starts again from index 0
+	if first, _ := First(q); first != time.Millisecond {
+		t.Errorf("expected %v, got %v", time.Millisecond, first)
+	}
+}
+
+func TestGenerateCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	q := Mapped(
+		Generate(func(i int) int { return i }), func(n int) int {
+			if n == 100 {
+				cancel()
+			}
+			return n
+		},
+	)
+
+	_, err := CountCtx(ctx, q)
+	if err != context.Canceled {
+		t.Errorf("expected %v, got %v", context.Canceled, err)
+	}
+}
+
+func TestUnfold(t *testing.T) {
+	// Fibonacci numbers below 50
+	q := Unfold(
+		[2]int{0, 1}, func(s [2]int) (int, [2]int, bool) {
+			return s[0], [2]int{s[1], s[0] + s[1]}, s[0] < 50
+		},
+	)
+	result := Slice(q)
+
+	expected := []int{0, 1, 1, 2, 3, 5, 8, 13, 21, 34}
+	if !slices.Equal(result, expected) {
+		t.Errorf("expected %v, got %v", expected, result)
+	}
+	if Count(q) != len(expected) {
+		t.Error("expected re-iteration to start from seed")
+	}
+}
@@ -1,5 +1,5 @@
 module github.com/polluxs/kk
 
-go 1.21
+go 1.23
 
 require golang.org/x/sync v0.10.0
@@ -10,13 +10,13 @@ type Group[K comparable, T any] struct {
 // This is a function (not a method) because it returns a different type.
 func GroupedBy[T any, K comparable](q *KKQuery[T], keyFn func(T) K) *KKQuery[Group[K, T]] {
 	return &KKQuery[Group[K, T]]{
-		iterate: func() Iterator[Group[K, T]] {
+		iterate: func(r *run) Iterator[Group[K, T]] {
 			// Materialize all items and group them
 			groups := make(map[K][]T)
 			var keys []K // maintain insertion order
 
-			iter := q.iterate()
-			for {
+			iter := q.iterate(r)
+			for !r.done() {
 				item, ok := iter()
 				if !ok {
 					break
@@ -41,3 +41,118 @@ func GroupedBy[T any, K comparable](q *KKQuery[T], keyFn func(T) K) *KKQuery[Gro
 		},
 	}
 }
+
+// AggregateBy folds the items of each key into one accumulator, starting each
+// key with seed, and returns a query of KeyValue pairs in the order keys were
+// first seen. Unlike GroupedBy, only one accumulator per key is kept in memory.
+// seed is copied for each key, so it should not be a slice or map that fn
+// modifies in place.
+// This is a function (not a method) because it returns a different type.
+func AggregateBy[T any, K comparable, A any](
+	q *KKQuery[T], keyFn func(T) K, seed A, fn func(acc A, item T) A,
+) *KKQuery[KeyValue[K, A]] {
+	return &KKQuery[KeyValue[K, A]]{
+		iterate: func(r *run) Iterator[KeyValue[K, A]] {
+			accs := make(map[K]A)
+			var keys []K // maintain insertion order
+
+			iter := q.iterate(r)
+			for !r.done() {
+				item, ok := iter()
+				if !ok {
+					break
+				}
+				key := keyFn(item)
+				acc, exists := accs[key]
+				if !exists {
+					keys = append(keys, key)
+					acc = seed
+				}
+				accs[key] = fn(acc, item)
+			}
+
+			index := 0
+			return func() (KeyValue[K, A], bool) {
+				if index >= len(keys) {
+					var zero KeyValue[K, A]
+					return zero, false
+				}
+				key := keys[index]
+				index++
+				return KeyValue[K, A]{Key: key, Value: accs[key]}, true
+			}
+		},
+	}
+}
+
+// CountBy counts the items of each key and returns a query of KeyValue pairs
+// in the order keys were first seen.
+// This is a function (not a method) because it returns a different type.
+func CountBy[T any, K comparable](q *KKQuery[T], keyFn func(T) K) *KKQuery[KeyValue[K, int]] {
+	return AggregateBy(q, keyFn, 0, func(count int, _ T) int { return count + 1 })
+}
+
+// SumBy sums the values produced by the selector function for each key and
+// returns a query of KeyValue pairs in the order keys were first seen.
+// This is a function (not a method) because it returns a different type.
+func SumBy[T any, K comparable, N Number](q *KKQuery[T], keyFn func(T) K, selector func(T) N) *KKQuery[KeyValue[K, N]] {
+	var zero N
+	return AggregateBy(q, keyFn, zero, func(sum N, item T) N { return sum + selector(item) })
+}
+
+// GroupAdjacent groups consecutive items with the same key, yielding each
+// group as soon as the key changes. For input sorted by key this gives the
+// same groups as GroupedBy without materializing the input, so memory is
+// bounded by the largest group and it works on infinite streams.
+// A key that appears again after a different key starts a new group.
+// This is a function (not a method) because it returns a different type.
+func GroupAdjacent[T any, K comparable](q *KKQuery[T], keyFn func(T) K) *KKQuery[Group[K, T]] {
+	return &KKQuery[Group[K, T]]{
+		iterate: func(r *run) Iterator[Group[K, T]] {
+			iter := q.iterate(r)
+
+			// The first item of the next group, already pulled
+			var next T
+			var nextKey K
+			hasNext := false
+			done := false
+			return func() (Group[K, T], bool) {
+				var zero Group[K, T]
+				if done {
+					return zero, false
+				}
+				if !hasNext {
+					item, ok := iter()
+					if !ok {
+						done = true
+						return zero, false
+					}
+					next, nextKey = item, keyFn(item)
+				}
+
+				group := Group[K, T]{Key: nextKey, Items: []T{next}}
+				hasNext = false
+				for {
+					item, ok := iter()
+					if !ok {
+						done = true
+						break
+					}
+					key := keyFn(item)
+					if key != group.Key {
+						next, nextKey, hasNext = item, key, true
+						break
+					}
+					group.Items = append(group.Items, item)
+				}
+
+				// A group cut short by a stopped run is incomplete
+				if r.stopped {
+					done = true
+					return zero, false
+				}
+				return group, true
+			}
+		},
+	}
+}
@@ -1,6 +1,7 @@
 package kk
 
 import (
+	"context"
 	"testing"
 )
 
@@ -253,3 +254,155 @@ func TestGroupByAllUniqueKeys(t *testing.T) {
 		}
 	}
 }
+
+func TestGroupedByCancelled(t *testing.T) {
+	ch := make(chan int)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go func() {
+		ch <- 1
+		cancel()
+	}()
+
+	_, err := SliceCtx(ctx, GroupedBy(QueryChan(ch), func(n int) int { return n % 2 }))
+	if err != context.Canceled {
+		t.Errorf("expected %v, got %v", context.Canceled, err)
+	}
+}
+
+type sale struct {
+	Country string
+	Amount  float64
+}
+
+var sales = []sale{{"nl", 10}, {"de", 5}, {"nl", 2.5}, {"fr", 1}, {"de", 4}}
+
+func TestCountBy(t *testing.T) {
+	result := Slice(CountBy(Query(sales), func(s sale) string { return s.Country }))
+
+	expected := []KeyValue[string, int]{{"nl", 2}, {"de", 2}, {"fr", 1}}
+	if len(result) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, result)
+	}
+	for i, kv := range result {
+		if kv != expected[i] {
+			t.Errorf("at index %d: expected %v, got %v", i, expected[i], kv)
+		}
+	}
+}
+
+func TestSumBy(t *testing.T) {
+	q := SumBy(Query(sales), func(s sale) string { return s.Country }, func(s sale) float64 { return s.Amount })
+	result := Slice(q)
+
+	expected := []KeyValue[string, float64]{{"nl", 12.5}, {"de", 9}, {"fr", 1}}
+	if len(result) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, result)
+	}
+	for i, kv := range result {
+		if kv != expected[i] {
+			t.Errorf("at index %d: expected %v, got %v", i, expected[i], kv)
+		}
+	}
+}
+
+func TestAggregateBy(t *testing.T) {
+	// Track the largest sale per country
+	q := AggregateBy(
+		Query(sales), func(s sale) string { return s.Country }, 0.0, func(largest float64, s sale) float64 {
+			return max(largest, s.Amount)
+		},
+	)
+	result := Slice(q)
+
+	if len(result) != 3 || result[0].Value != 10 || result[1].Value != 5 || result[2].Value != 1 {
+		t.Errorf("expected [{nl 10} {de 5} {fr 1}], got %v", result)
+	}
+}
+
+func TestAggregateByReiterate(t *testing.T) {
+	q := CountBy(Query(sales), func(s sale) string { return s.Country })
+
+	first := Slice(q)
+	second := Slice(q)
+	if first[0].Value != 2 || second[0].Value != 2 {
+		t.Errorf("expected fresh counts per iteration, got %v and %v", first, second)
+	}
+}
+
+func TestCountByError(t *testing.T) {
+	q := CountBy(MappedErr(Query([]int{1, 2, 3}), failOn(3)), func(n int) bool { return n%2 == 0 })
+	result, err := SliceErr(q)
+
+	if err == nil || err.Error() != "bad item 3" {
+		t.Errorf("expected bad item 3, got %v", err)
+	}
+	if len(result) != 0 {
+		t.Errorf("expected no partial counts, got %v", result)
+	}
+}
+
+func TestGroupAdjacent(t *testing.T) {
+	input := []string{"a1", "a2", "b1", "c1", "c2", "c3", "a3"}
+	groups := Slice(GroupAdjacent(Query(input), func(s string) byte { return s[0] }))
+
+	expectedKeys := []byte{'a', 'b', 'c', 'a'}
+	expectedSizes := []int{2, 1, 3, 1}
+	if len(groups) != len(expectedKeys) {
+		t.Fatalf("expected %d groups, got %d", len(expectedKeys), len(groups))
+	}
+	for i, g := range groups {
+		if g.Key != expectedKeys[i] || len(g.Items) != expectedSizes[i] {
+			t.Errorf("group %d: expected key %c with %d items, got %c with %v", i, expectedKeys[i], expectedSizes[i], g.Key, g.Items)
+		}
+	}
+}
+
+func TestGroupAdjacentEmpty(t *testing.T) {
+	groups := Slice(GroupAdjacent(Query([]int{}), func(n int) int { return n }))
+
+	if len(groups) != 0 {
+		t.Errorf("expected no groups, got %v", groups)
+	}
+}
+
+func TestGroupAdjacentInfinite(t *testing.T) {
+	// Groups of ten from an infinite sorted stream
+	q := GroupAdjacent(Generate(func(i int) int { return i }), func(n int) int { return n / 10 })
+	groups := Slice(q.Take(3))
+
+	if len(groups) != 3 {
+		t.Fatalf("expected 3 groups, got %d", len(groups))
+	}
+	if groups[2].Key != 2 || len(groups[2].Items) != 10 || groups[2].Items[0] != 20 {
+		t.Errorf("expected group 2 with items 20..29, got %v", groups[2])
+	}
+}
+
+func TestGroupAdjacentStreams(t *testing.T) {
+	ch := make(chan int)
+	go func() {
+		ch <- 1
+		ch <- 1
+		ch <- 2
+		// The first group is yielded before the channel is closed
+	}()
+
+	g, ok := First(GroupAdjacent(QueryChan(ch), func(n int) int { return n }))
+	if !ok || g.Key != 1 || len(g.Items) != 2 {
+		t.Errorf("expected group 1 with 2 items, got %v", g)
+	}
+}
+
+func TestGroupAdjacentError(t *testing.T) {
+	q := GroupAdjacent(MappedErr(Query([]int{1, 1, 2, 3, 3}), failOn(3)), func(n int) int { return n })
+	groups, err := SliceErr(q)
+
+	if err == nil || err.Error() != "bad item 3" {
+		t.Errorf("expected bad item 3, got %v", err)
+	}
+	// The group for key 2 was cut short by the error and is not yielded
+	if len(groups) != 1 || groups[0].Key != 1 {
+		t.Errorf("expected only the group for key 1, got %v", groups)
+	}
+}
@@ -0,0 +1,201 @@
+package kk
+
+import "cmp"
+
+// Join correlates items of outer and inner with equal keys and yields
+// result for each matching pair, in outer order and then inner order.
+// Outer items without a match are left out.
+// The inner query is materialized into a hash table; the outer query is streamed.
+// This is a function (not a method) because it returns a different type.
+func Join[O any, I any, K comparable, R any](
+	outer *KKQuery[O], inner *KKQuery[I], outerKey func(O) K, innerKey func(I) K, result func(O, I) R,
+) *KKQuery[R] {
+	return &KKQuery[R]{
+		iterate: func(r *run) Iterator[R] {
+			lookup := buildLookup(r, inner, innerKey)
+			iter := outer.iterate(r)
+			var current O
+			var matches []I
+			index := 0
+			return func() (R, bool) {
+				for {
+					if index < len(matches) {
+						item := matches[index]
+						index++
+						return result(current, item), true
+					}
+					// The hash table is incomplete if the run stopped while building it
+					if r.stopped {
+						var zero R
+						return zero, false
+					}
+					item, ok := iter()
+					if !ok {
+						var zero R
+						return zero, false
+					}
+					current = item
+					matches = lookup[outerKey(item)]
+					index = 0
+				}
+			}
+		},
+	}
+}
+
+// LeftJoin is like Join but also yields outer items without a match, calling
+// result with the zero value of I and matched set to false.
+// This is a function (not a method) because it returns a different type.
+func LeftJoin[O any, I any, K comparable, R any](
+	outer *KKQuery[O], inner *KKQuery[I], outerKey func(O) K, innerKey func(I) K,
+	result func(o O, i I, matched bool) R,
+) *KKQuery[R] {
+	return &KKQuery[R]{
+		iterate: func(r *run) Iterator[R] {
+			lookup := buildLookup(r, inner, innerKey)
+			iter := outer.iterate(r)
+			var current O
+			var matches []I
+			index := 0
+			return func() (R, bool) {
+				if index < len(matches) {
+					item := matches[index]
+					index++
+					return result(current, item, true), true
+				}
+				// The hash table is incomplete if the run stopped while building it
+				if r.stopped {
+					var zero R
+					return zero, false
+				}
+				item, ok := iter()
+				if !ok {
+					var zero R
+					return zero, false
+				}
+				current = item
+				matches = lookup[outerKey(item)]
+				index = 0
+				if len(matches) == 0 {
+					var zero I
+					return result(current, zero, false), true
+				}
+				index = 1
+				return result(current, matches[0], true), true
+			}
+		},
+	}
+}
+
+// GroupJoin yields result once for each outer item, with every inner item
+// that has the same key, in inner order. The slice is empty when nothing matches.
+// This is a function (not a method) because it returns a different type.
+func GroupJoin[O any, I any, K comparable, R any](
+	outer *KKQuery[O], inner *KKQuery[I], outerKey func(O) K, innerKey func(I) K, result func(O, []I) R,
+) *KKQuery[R] {
+	return &KKQuery[R]{
+		iterate: func(r *run) Iterator[R] {
+			lookup := buildLookup(r, inner, innerKey)
+			iter := outer.iterate(r)
+			return func() (R, bool) {
+				// The hash table is incomplete if the run stopped while building it
+				if r.stopped {
+					var zero R
+					return zero, false
+				}
+				item, ok := iter()
+				if !ok {
+					var zero R
+					return zero, false
+				}
+				return result(item, lookup[outerKey(item)]), true
+			}
+		},
+	}
+}
+
+// MergeJoin is like Join for inputs that are both sorted by key in ascending
+// order. Neither side is materialized: it holds only the inner items that
+// share the current key, so it works on large or infinite sorted streams.
+// Results are undefined if either input is not sorted.
+// This is a function (not a method) because it returns a different type.
+func MergeJoin[O any, I any, K cmp.Ordered, R any](
+	outer *KKQuery[O], inner *KKQuery[I], outerKey func(O) K, innerKey func(I) K, result func(O, I) R,
+) *KKQuery[R] {
+	return &KKQuery[R]{
+		iterate: func(r *run) Iterator[R] {
+			iterOuter := outer.iterate(r)
+			iterInner := inner.iterate(r)
+
+			// The next inner item not yet added to a group
+			var next I
+			var nextKey K
+			hasNext, innerStarted := false, false
+			advance := func() {
+				innerStarted = true
+				next, hasNext = iterInner()
+				if hasNext {
+					nextKey = innerKey(next)
+				}
+			}
+
+			// The inner items with the key of the current outer item
+			var group []I
+			var groupKey K
+			hasGroup := false
+
+			var current O
+			index := 0
+			done := false
+			return func() (R, bool) {
+				var zero R
+				for !done {
+					if index < len(group) {
+						item := group[index]
+						index++
+						return result(current, item), true
+					}
+
+					item, ok := iterOuter()
+					if !ok {
+						done = true
+						break
+					}
+					key := outerKey(item)
+					if !hasGroup || key != groupKey {
+						if !innerStarted {
+							advance()
+						}
+						for hasNext && nextKey < key {
+							advance()
+						}
+						group = nil
+						for hasNext && nextKey == key {
+							group = append(group, next)
+							advance()
+						}
+						groupKey, hasGroup = key, true
+						if r.stopped || (len(group) == 0 && !hasNext) {
+							// No later outer item can match once the inner side is exhausted
+							done = true
+							break
+						}
+					}
+					current = item
+					index = 0
+				}
+				return zero, false
+			}
+		},
+	}
+}
+
+// buildLookup materializes q into a map from key to items, in query order.
+func buildLookup[T any, K comparable](r *run, q *KKQuery[T], keyFn func(T) K) map[K][]T {
+	lookup := make(map[K][]T)
+	for _, item := range collect(r, q) {
+		key := keyFn(item)
+		lookup[key] = append(lookup[key], item)
+	}
+	return lookup
+}
@@ -0,0 +1,158 @@
+package kk
+
+import (
+	"fmt"
+	"testing"
+)
+
+type joinUser struct {
+	ID   int
+	Name string
+}
+
+type joinOrder struct {
+	UserID int
+	Item   string
+}
+
+var (
+	joinUsers = []joinUser{{1, "ann"}, {2, "bob"}, {3, "cid"}}
+	// Sorted by UserID for MergeJoin
+	joinOrders = []joinOrder{{1, "pen"}, {1, "ink"}, {3, "cup"}, {4, "hat"}}
+)
+
+func userID(u joinUser) int     { return u.ID }
+func orderUser(o joinOrder) int { return o.UserID }
+
+func expectStrings(t *testing.T, expected []string, result []string) {
+	t.Helper()
+	if len(result) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, result)
+	}
+	for i, v := range result {
+		if v != expected[i] {
+			t.Errorf("at index %d: expected %s, got %s", i, expected[i], v)
+		}
+	}
+}
+
+func TestJoin(t *testing.T) {
+	q := Join(
+		Query(joinUsers), Query(joinOrders), userID, orderUser,
+		func(u joinUser, o joinOrder) string { return u.Name + ":" + o.Item },
+	)
+
+	expectStrings(t, []string{"ann:pen", "ann:ink", "cid:cup"}, Slice(q))
+}
+
+func TestJoinReiterate(t *testing.T) {
+	q := Join(
+		Query(joinUsers), Query(joinOrders), userID, orderUser,
+		func(u joinUser, o joinOrder) string { return o.Item },
+	)
+
+	if Count(q) != 3 || Count(q) != 3 {
+		t.Error("expected 3 results on each iteration")
+	}
+}
+
+func TestJoinInnerError(t *testing.T) {
+	inner := MappedErr(Query([]int{1, 2, 3}), failOn(2))
+	q := Join(
+		Query([]int{1, 3}), inner, func(n int) int { return n }, func(n int) int { return n },
+		func(a int, b int) int { return a + b },
+	)
+	result, err := SliceErr(q)
+
+	if len(result) != 0 {
+		t.Errorf("expected no results from an incomplete hash table, got %v", result)
+	}
+	if err == nil || err.Error() != "bad item 2" {
+		t.Errorf("expected bad item 2, got %v", err)
+	}
+}
+
+func TestLeftJoin(t *testing.T) {
+	q := LeftJoin(
+		Query(joinUsers), Query(joinOrders), userID, orderUser,
+		func(u joinUser, o joinOrder, matched bool) string {
+			if !matched {
+				return u.Name + ":-"
+			}
+			return u.Name + ":" + o.Item
+		},
+	)
+
+	expectStrings(t, []string{"ann:pen", "ann:ink", "bob:-", "cid:cup"}, Slice(q))
+}
+
+func TestGroupJoin(t *testing.T) {
+	q := GroupJoin(
+		Query(joinUsers), Query(joinOrders), userID, orderUser,
+		func(u joinUser, orders []joinOrder) string { return fmt.Sprintf("%s:%d", u.Name, len(orders)) },
+	)
+
+	expectStrings(t, []string{"ann:2", "bob:0", "cid:1"}, Slice(q))
+}
+
+func TestMergeJoin(t *testing.T) {
+	q := MergeJoin(
+		Query(joinUsers), Query(joinOrders), userID, orderUser,
+		func(u joinUser, o joinOrder) string { return u.Name + ":" + o.Item },
+	)
+
+	expectStrings(t, []string{"ann:pen", "ann:ink", "cid:cup"}, Slice(q))
+}
+
+func TestMergeJoinDuplicateOuterKeys(t *testing.T) {
+	q := MergeJoin(
+		Query([]int{1, 1, 2, 5}), Query([]int{0, 1, 1, 2, 3}),
+		func(n int) int { return n }, func(n int) int { return n },
+		func(a int, b int) string { return fmt.Sprintf("%d-%d", a, b) },
+	)
+
+	expectStrings(t, []string{"1-1", "1-1", "1-1", "1-1", "2-2"}, Slice(q))
+}
+
+func TestMergeJoinStreams(t *testing.T) {
+	// Both sides are infinite; only matching keys are ever buffered
+	evens := Generate(func(i int) int { return i * 2 })
+	threes := Generate(func(i int) int { return i * 3 })
+	q := MergeJoin(
+		evens, threes, func(n int) int { return n }, func(n int) int { return n },
+		func(a int, b int) int { return a },
+	).Take(4)
+	result := Slice(q)
+
+	expected := []int{0, 6, 12, 18}
+	if len(result) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, result)
+	}
+	for i, v := range result {
+		if v != expected[i] {
+			t.Errorf("at index %d: expected %d, got %d", i, expected[i], v)
+		}
+	}
+}
+
+func TestMergeJoinStopsWhenInnerExhausted(t *testing.T) {
+	pulled := 0
+	outer := Mapped(
+		Generate(func(i int) int { return i }), func(n int) int {
+			pulled++
+			return n
+		},
+	)
+	q := MergeJoin(
+		outer, Query([]int{1, 2}), func(n int) int { return n }, func(n int) int { return n },
+		func(a int, b int) int { return a },
+	)
+	result := Slice(q)
+
+	if len(result) != 2 {
+		t.Errorf("expected 2 results, got %v", result)
+	}
+	if pulled != 4 {
+		t.Errorf("expected 4 outer items pulled, got %d", pulled)
+	}
+}
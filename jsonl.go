@@ -0,0 +1,76 @@
+package kk
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+// JSONLOptions configures how QueryJSONL decodes its input.
+type JSONLOptions struct {
+	// Malformed controls what happens to lines that fail to decode:
+	// StopOnError ends iteration, CollectErrors skips the line and reports
+	// its error, and SkipErrors skips the line silently.
+	// Errors are reported as *LineError.
+	Malformed ErrorPolicy
+	// MaxLineSize is the longest line that can be read.
+	// Defaults to bufio.MaxScanTokenSize.
+	MaxLineSize int
+}
+
+// QueryJSONL creates a KKQuery that decodes each line of rd as JSON into T.
+// Blank lines are ignored. Read errors are reported to the terminal operation.
+// If rd is an io.Closer it is closed when iteration ends, including on early stop.
+// The reader can only be consumed once; use Cached to iterate more than once.
+func QueryJSONL[T any](rd io.Reader, opts JSONLOptions) *KKQuery[T] {
+	lines := QueryReader(rd, ReaderOptions{MaxTokenSize: opts.MaxLineSize})
+	return &KKQuery[T]{
+		iterate: func(r *run) Iterator[T] {
+			iter := lines.iterate(r)
+			lineNum := 0
+			return func() (T, bool) {
+				for {
+					line, ok := iter()
+					if !ok {
+						var zero T
+						return zero, false
+					}
+					lineNum++
+					if strings.TrimSpace(line) == "" {
+						continue
+					}
+					var item T
+					if err := json.Unmarshal([]byte(line), &item); err != nil {
+						if r.failWith(opts.Malformed, &LineError{Line: lineNum, Err: err}) {
+							continue
+						}
+						var zero T
+						return zero, false
+					}
+					return item, true
+				}
+			}
+		},
+	}
+}
+
+// WriteJSONL encodes each item of the query to w as one line of JSON.
+// Returns the first encoding or write error, or the error reported by the query chain.
+func WriteJSONL[T any](w io.Writer, q *KKQuery[T]) error {
+	r := newRun(context.Background())
+	defer r.close()
+
+	enc := json.NewEncoder(w)
+	iter := q.iterate(r)
+	for {
+		item, ok := iter()
+		if !ok {
+			break
+		}
+		if err := enc.Encode(item); err != nil {
+			return err
+		}
+	}
+	return r.err()
+}
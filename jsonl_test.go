@@ -0,0 +1,134 @@
+package kk
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+type jsonlRecord struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+const jsonlInput = `{"id":1,"name":"a"}
+{"id":2,"name":"b"}
+not json
+
+{"id":4,"name":"d"}
+`
+
+func TestQueryJSONL(t *testing.T) {
+	input := "{\"id\":1,\"name\":\"a\"}\n\n{\"id\":2,\"name\":\"b\"}\n"
+	result, err := SliceErr(QueryJSONL[jsonlRecord](strings.NewReader(input), JSONLOptions{}))
+
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if len(result) != 2 {
+		t.Fatalf("expected length 2, got %d", len(result))
+	}
+	if result[0].ID != 1 || result[1].Name != "b" {
+		t.Errorf("unexpected records %v", result)
+	}
+}
+
+func TestQueryJSONLMalformedStop(t *testing.T) {
+	q := QueryJSONL[jsonlRecord](strings.NewReader(jsonlInput), JSONLOptions{})
+	result, err := SliceErr(q)
+
+	var lineErr *LineError
+	if !errors.As(err, &lineErr) {
+		t.Fatalf("expected *LineError, got %v", err)
+	}
+	if lineErr.Line != 3 {
+		t.Errorf("expected line 3, got %d", lineErr.Line)
+	}
+	if len(result) != 2 {
+		t.Errorf("expected length 2, got %d", len(result))
+	}
+}
+
+func TestQueryJSONLMalformedSkip(t *testing.T) {
+	q := QueryJSONL[jsonlRecord](strings.NewReader(jsonlInput), JSONLOptions{Malformed: SkipErrors})
+	result, err := SliceErr(q)
+
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if len(result) != 3 {
+		t.Errorf("expected length 3, got %d", len(result))
+	}
+}
+
+func TestQueryJSONLMalformedCollect(t *testing.T) {
+	input := jsonlInput + "{\"id\":\"x\"}\n"
+	q := QueryJSONL[jsonlRecord](strings.NewReader(input), JSONLOptions{Malformed: CollectErrors})
+	result, err := SliceErr(q)
+
+	if len(result) != 3 {
+		t.Errorf("expected length 3, got %d", len(result))
+	}
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if !strings.Contains(err.Error(), "line 3:") || !strings.Contains(err.Error(), "line 6:") {
+		t.Errorf("expected errors for lines 3 and 6, got %v", err)
+	}
+}
+
+func TestQueryJSONLParallelByBatch(t *testing.T) {
+	var buf strings.Builder
+	for i := 0; i < 10; i++ {
+		buf.WriteString(`{"id":1}` + "\n")
+	}
+	var total atomic.Int32
+
+	err := ParallelByBatch(
+		context.Background(), QueryJSONL[jsonlRecord](strings.NewReader(buf.String()), JSONLOptions{}), 3, 2,
+		func(ctx context.Context, batch []jsonlRecord) error {
+			for _, rec := range batch {
+				total.Add(int32(rec.ID))
+			}
+			return nil
+		},
+	)
+
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if total.Load() != 10 {
+		t.Errorf("expected total 10, got %d", total.Load())
+	}
+}
+
+func TestWriteJSONL(t *testing.T) {
+	var buf bytes.Buffer
+	records := []jsonlRecord{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}}
+
+	if err := WriteJSONL(&buf, Query(records)); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expected := "{\"id\":1,\"name\":\"a\"}\n{\"id\":2,\"name\":\"b\"}\n"
+	if buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+
+	// Round trip
+	result := Slice(QueryJSONL[jsonlRecord](&buf, JSONLOptions{}))
+	if len(result) != 2 || result[1] != records[1] {
+		t.Errorf("expected %v, got %v", records, result)
+	}
+}
+
+func TestWriteJSONLEncodeError(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteJSONL(&buf, Query([]any{1, func() {}}))
+
+	if err == nil {
+		t.Error("expected error, got nil")
+	}
+}
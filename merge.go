@@ -0,0 +1,156 @@
+package kk
+
+import (
+	"context"
+	"sync"
+)
+
+// Merge combines queries by iterating them concurrently and yielding items in
+// the order they arrive. Unlike Concat, a slow or blocked input does not hold
+// back the others. The merged query ends when every input is exhausted.
+// Each input runs in its own goroutine, started on the first pull. When
+// iteration ends, including on early stop or cancellation of the terminal's
+// context, every input is cancelled and closed before the terminal returns.
+// Errors from the inputs are reported once all inputs have finished; under
+// StopOnError the first error also cancels the other inputs. A panic in an
+// input cancels the others and is raised again by the merged query once they
+// have stopped.
+func Merge[T any](queries ...*KKQuery[T]) *KKQuery[T] {
+	return &KKQuery[T]{
+		iterate: func(r *run) Iterator[T] {
+			var m *merger[T]
+			done := false
+			return func() (T, bool) {
+				var zero T
+				if done {
+					return zero, false
+				}
+				if m == nil {
+					m = startMerge(r, queries)
+				}
+				select {
+				case item, ok := <-m.out:
+					if ok {
+						return item, true
+					}
+				case <-r.ctx.Done():
+				}
+				done = true
+				m.finish(r)
+				return zero, false
+			}
+		},
+	}
+}
+
+// MergeChan combines channels into one query that yields items in the order
+// they arrive, ending when every channel is closed. See Merge.
+func MergeChan[T any](chs ...<-chan T) *KKQuery[T] {
+	queries := make([]*KKQuery[T], len(chs))
+	for i, ch := range chs {
+		queries[i] = QueryChan(ch)
+	}
+	return Merge(queries...)
+}
+
+// merger runs the inputs of a Merge and collects their errors and the first
+// panic.
+type merger[T any] struct {
+	out      chan T
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+	mu       sync.Mutex
+	errs     []error
+	panicked bool
+	panicVal any
+}
+
+// startMerge starts one goroutine per input query. The goroutines stop when
+// the run is closed.
+func startMerge[T any](r *run, queries []*KKQuery[T]) *merger[T] {
+	ctx, cancel := context.WithCancel(r.ctx)
+	m := &merger[T]{out: make(chan T), cancel: cancel}
+	r.onClose(
+		func() {
+			cancel()
+			m.wg.Wait()
+		},
+	)
+
+	for _, q := range queries {
+		m.wg.Add(1)
+		go func(q *KKQuery[T]) {
+			defer m.wg.Done()
+			m.drain(ctx, r, q)
+		}(q)
+	}
+
+	go func() {
+		m.wg.Wait()
+		close(m.out)
+	}()
+	return m
+}
+
+// drain iterates one input in its own run and sends its items to out.
+func (m *merger[T]) drain(ctx context.Context, parent *run, q *KKQuery[T]) {
+	r := parent.detached(ctx)
+	defer r.close()
+	defer func() {
+		if p := recover(); p != nil {
+			m.mu.Lock()
+			if !m.panicked {
+				m.panicked = true
+				m.panicVal = p
+			}
+			m.mu.Unlock()
+			m.cancel()
+		}
+	}()
+
+	iter := q.iterate(r)
+	for !r.done() {
+		item, ok := iter()
+		if !ok {
+			break
+		}
+		select {
+		case m.out <- item:
+		case <-ctx.Done():
+		}
+	}
+
+	// Cancellation is reported by the merged query itself, not by each input
+	errs := r.errs
+	if ctx.Err() != nil && len(errs) > 0 && errs[len(errs)-1] == ctx.Err() {
+		errs = errs[:len(errs)-1]
+	}
+	if len(errs) == 0 {
+		return
+	}
+	m.mu.Lock()
+	m.errs = append(m.errs, errs...)
+	m.mu.Unlock()
+	if r.policy == StopOnError {
+		m.cancel()
+	}
+}
+
+// finish waits for the inputs to stop and reports their errors to r. If an
+// input panicked, finish panics with the same value.
+func (m *merger[T]) finish(r *run) {
+	m.cancel()
+	m.wg.Wait()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.panicked {
+		panic(m.panicVal)
+	}
+	r.done()
+	for _, err := range m.errs {
+		if r.stopped {
+			break
+		}
+		r.fail(err)
+	}
+}
@@ -0,0 +1,178 @@
+package kk
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMerge(t *testing.T) {
+	q := Merge(Query([]int{1, 2, 3}), Query([]int{4, 5}), Query([]int{}))
+	result := Slice(q)
+
+	slices.Sort(result)
+	if !slices.Equal(result, []int{1, 2, 3, 4, 5}) {
+		t.Errorf("expected [1 2 3 4 5], got %v", result)
+	}
+
+	// Re-iterating runs the inputs again
+	if Count(q) != 5 {
+		t.Errorf("expected count 5, got %d", Count(q))
+	}
+}
+
+func TestMergeEmpty(t *testing.T) {
+	if result := Slice(Merge[int]()); len(result) != 0 {
+		t.Errorf("expected empty slice, got %v", result)
+	}
+}
+
+func TestMergeChanArrivalOrder(t *testing.T) {
+	slow := make(chan string)
+	fast := make(chan string)
+	go func() {
+		defer close(fast)
+		fast <- "fast1"
+		fast <- "fast2"
+	}()
+
+	iterated := make(chan []string)
+	go func() {
+		iterated <- Slice(MergeChan(slow, fast).Take(2))
+	}()
+
+	// The slow channel never sends, yet items from the fast one arrive
+	select {
+	case result := <-iterated:
+		if !slices.Equal(result, []string{"fast1", "fast2"}) {
+			t.Errorf("expected [fast1 fast2], got %v", result)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("items from the second channel were held back by the first")
+	}
+	close(slow)
+}
+
+func TestMergeEarlyStopClosesInputs(t *testing.T) {
+	var closed atomic.Int32
+	infinite := func() *KKQuery[int] {
+		return Generate(func(i int) int { return i }).OnClose(func() { closed.Add(1) })
+	}
+
+	result := Slice(Merge(infinite(), infinite(), infinite()).Take(10))
+	if len(result) != 10 {
+		t.Errorf("expected length 10, got %d", len(result))
+	}
+	if closed.Load() != 3 {
+		t.Errorf("expected all 3 inputs closed, got %d", closed.Load())
+	}
+}
+
+func TestMergeCancelled(t *testing.T) {
+	ch1 := make(chan int)
+	ch2 := make(chan int)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := SliceCtx(ctx, MergeChan(ch1, ch2))
+	if err != context.DeadlineExceeded {
+		t.Errorf("expected %v, got %v", context.DeadlineExceeded, err)
+	}
+}
+
+func TestMergeErrorStopsInputs(t *testing.T) {
+	expectedErr := errors.New("input failed")
+	failing := MappedErr(
+		Query([]int{1}), func(n int) (int, error) {
+			return 0, expectedErr
+		},
+	)
+	blocked := QueryChan(make(chan int))
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := SliceErr(Merge(failing, blocked))
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err != expectedErr {
+			t.Errorf("expected %v, got %v", expectedErr, err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the error to cancel the other inputs")
+	}
+}
+
+func TestMergePanicReachesCaller(t *testing.T) {
+	panicking := Mapped(
+		Query([]int{1}), func(n int) int {
+			panic("boom")
+		},
+	)
+	var closed atomic.Int32
+	blocked := QueryChan(make(chan int)).OnClose(func() { closed.Add(1) })
+
+	done := make(chan any, 1)
+	go func() {
+		defer func() {
+			done <- recover()
+		}()
+		Slice(Merge(panicking, blocked))
+	}()
+
+	select {
+	case p := <-done:
+		if p != "boom" {
+			t.Errorf("expected panic boom, got %v", p)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the panic to cancel the other inputs")
+	}
+	if closed.Load() != 1 {
+		t.Errorf("expected the other input closed before the panic, got %d closes", closed.Load())
+	}
+}
+
+func TestMergeCollectErrors(t *testing.T) {
+	q := Merge(
+		MappedErr(Query([]int{1, 2}), failOn(2)),
+		MappedErr(Query([]int{3, 4}), failOn(4)),
+	).OnError(CollectErrors)
+	result, err := SliceErr(q)
+
+	slices.Sort(result)
+	if !slices.Equal(result, []int{1, 3}) {
+		t.Errorf("expected [1 3], got %v", result)
+	}
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if strings.Count(err.Error(), "bad item") != 2 {
+		t.Errorf("expected 2 errors, got %v", err)
+	}
+}
+
+func TestMergeParallel(t *testing.T) {
+	var sum atomic.Int64
+	q := Merge(Range(0, 100, 1), Range(100, 200, 1))
+
+	err := Parallel(
+		context.Background(), q, 4, func(ctx context.Context, n int) error {
+			sum.Add(int64(n))
+			return nil
+		},
+	)
+
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if sum.Load() != 19900 {
+		t.Errorf("expected sum 19900, got %d", sum.Load())
+	}
+}
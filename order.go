@@ -15,8 +15,8 @@ type OrderedQuery[T any] struct {
 func SortedBy[T any, K cmp.Ordered](q *KKQuery[T], keyFn func(T) K) *OrderedQuery[T] {
 	return &OrderedQuery[T]{
 		KKQuery: &KKQuery[T]{
-			iterate: func() Iterator[T] {
-				items := Slice(q)
+			iterate: func(r *run) Iterator[T] {
+				items := collect(r, q)
 				sort.Slice(
 					items, func(i, j int) bool {
 						return keyFn(items[i]) < keyFn(items[j])
@@ -53,8 +53,8 @@ func SortedBy[T any, K cmp.Ordered](q *KKQuery[T], keyFn func(T) K) *OrderedQuer
 func SortedByDesc[T any, K cmp.Ordered](q *KKQuery[T], keyFn func(T) K) *OrderedQuery[T] {
 	return &OrderedQuery[T]{
 		KKQuery: &KKQuery[T]{
-			iterate: func() Iterator[T] {
-				items := Slice(q)
+			iterate: func(r *run) Iterator[T] {
+				items := collect(r, q)
 				sort.Slice(
 					items, func(i, j int) bool {
 						return keyFn(items[i]) > keyFn(items[j])
@@ -104,8 +104,8 @@ func ThenBy[T any, K cmp.Ordered](oq *OrderedQuery[T], keyFn func(T) K) *Ordered
 
 	return &OrderedQuery[T]{
 		KKQuery: &KKQuery[T]{
-			iterate: func() Iterator[T] {
-				items := Slice(oq.KKQuery)
+			iterate: func(r *run) Iterator[T] {
+				items := collect(r, oq.KKQuery)
 				sort.Slice(
 					items, func(i, j int) bool {
 						for _, cmp := range newComparators {
@@ -150,8 +150,8 @@ func ThenByDescending[T any, K cmp.Ordered](oq *OrderedQuery[T], keyFn func(T) K
 
 	return &OrderedQuery[T]{
 		KKQuery: &KKQuery[T]{
-			iterate: func() Iterator[T] {
-				items := Slice(oq.KKQuery)
+			iterate: func(r *run) Iterator[T] {
+				items := collect(r, oq.KKQuery)
 				sort.Slice(
 					items, func(i, j int) bool {
 						for _, cmp := range newComparators {
@@ -0,0 +1,156 @@
+package kk
+
+import (
+	"context"
+	"sync"
+)
+
+// PageOptions configures QueryPages.
+type PageOptions struct {
+	// Prefetch is the number of pages fetched ahead of the consumer in a
+	// background goroutine, counting the fetch in flight. Zero fetches each
+	// page only when it is needed.
+	Prefetch int
+}
+
+// QueryPages creates a KKQuery that lazily flattens the pages returned by fetch.
+// fetch receives the terminal's context and a cursor, starting with start, and
+// returns the page's items and the cursor of the next page. Iteration ends
+// after a page whose next cursor is the zero value of C.
+// A fetch error ends iteration and is reported to the terminal operation.
+// When iteration stops early, prefetching is cancelled and no further pages are fetched.
+func QueryPages[T any, C comparable](
+	start C, fetch func(ctx context.Context, cursor C) ([]T, C, error), opts PageOptions,
+) *KKQuery[T] {
+	return &KKQuery[T]{
+		iterate: func(r *run) Iterator[T] {
+			var nextPage func() ([]T, bool)
+			if opts.Prefetch > 0 {
+				nextPage = prefetchPages(r, start, fetch, opts.Prefetch)
+			} else {
+				nextPage = fetchPages(r, start, fetch)
+			}
+
+			var items []T
+			index := 0
+			return func() (T, bool) {
+				for index >= len(items) {
+					page, ok := nextPage()
+					if !ok {
+						var zero T
+						return zero, false
+					}
+					items = page
+					index = 0
+				}
+				item := items[index]
+				index++
+				return item, true
+			}
+		},
+	}
+}
+
+// fetchPages returns a function that fetches the next page on demand.
+func fetchPages[T any, C comparable](
+	r *run, cursor C, fetch func(ctx context.Context, cursor C) ([]T, C, error),
+) func() ([]T, bool) {
+	var zero C
+	done := false
+	return func() ([]T, bool) {
+		if done || r.done() {
+			return nil, false
+		}
+		items, next, err := fetch(r.ctx, cursor)
+		if err != nil {
+			done = true
+			r.fail(err)
+			return nil, false
+		}
+		cursor = next
+		done = next == zero
+		return items, true
+	}
+}
+
+// page is the result of one fetch by the prefetching goroutine.
+type page[T any] struct {
+	items []T
+	err   error
+}
+
+// prefetchPages returns a function that receives pages fetched by a
+// background goroutine, which runs up to depth pages ahead: depth-1 waiting
+// in the channel and one being fetched. The goroutine starts on the first
+// call and is stopped when the run is closed. A panic in fetch is raised
+// again on the consuming goroutine after the pages fetched before it.
+func prefetchPages[T any, C comparable](
+	r *run, start C, fetch func(ctx context.Context, cursor C) ([]T, C, error), depth int,
+) func() ([]T, bool) {
+	ctx, cancel := context.WithCancel(r.ctx)
+	pages := make(chan page[T], depth-1)
+	var wg sync.WaitGroup
+	// Set before pages is closed, so the receiver sees them once it drains pages
+	var panicked bool
+	var panicVal any
+	r.onClose(
+		func() {
+			cancel()
+			wg.Wait()
+		},
+	)
+
+	produce := func() {
+		defer wg.Done()
+		defer close(pages)
+		defer func() {
+			if p := recover(); p != nil {
+				panicked = true
+				panicVal = p
+			}
+		}()
+
+		var zero C
+		cursor := start
+		for {
+			items, next, err := fetch(ctx, cursor)
+			select {
+			case pages <- page[T]{items: items, err: err}:
+			case <-ctx.Done():
+				return
+			}
+			if err != nil || next == zero {
+				return
+			}
+			cursor = next
+		}
+	}
+
+	started := false
+	done := false
+	return func() ([]T, bool) {
+		if done {
+			return nil, false
+		}
+		if !started {
+			started = true
+			wg.Add(1)
+			go produce()
+		}
+		p, ok := <-pages
+		if !ok {
+			done = true
+			if panicked {
+				panic(panicVal)
+			}
+			r.done()
+			return nil, false
+		}
+		if p.err != nil {
+			done = true
+			r.fail(p.err)
+			return nil, false
+		}
+		return p.items, true
+	}
+}
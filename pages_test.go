@@ -0,0 +1,177 @@
+package kk
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// numberPages serves the numbers 1..total in pages of size, using the
+// offset of the next page as cursor.
+func numberPages(total, size int, fetches *atomic.Int32) func(context.Context, int) ([]int, int, error) {
+	return func(ctx context.Context, offset int) ([]int, int, error) {
+		fetches.Add(1)
+		var items []int
+		for i := offset; i < offset+size && i < total; i++ {
+			items = append(items, i+1)
+		}
+		next := offset + size
+		if next >= total {
+			next = 0
+		}
+		return items, next, nil
+	}
+}
+
+func TestQueryPages(t *testing.T) {
+	for _, prefetch := range []int{0, 1, 3} {
+		var fetches atomic.Int32
+		q := QueryPages(0, numberPages(10, 3, &fetches), PageOptions{Prefetch: prefetch})
+		result, err := SliceErr(q)
+
+		if err != nil {
+			t.Errorf("prefetch %d: expected no error, got %v", prefetch, err)
+		}
+		if len(result) != 10 || result[0] != 1 || result[9] != 10 {
+			t.Errorf("prefetch %d: expected 1..10, got %v", prefetch, result)
+		}
+		if fetches.Load() != 4 {
+			t.Errorf("prefetch %d: expected 4 fetches, got %d", prefetch, fetches.Load())
+		}
+	}
+}
+
+func TestQueryPagesLazy(t *testing.T) {
+	var fetches atomic.Int32
+	q := QueryPages(0, numberPages(100, 10, &fetches), PageOptions{})
+	result := Slice(q.Take(15))
+
+	if len(result) != 15 {
+		t.Errorf("expected length 15, got %d", len(result))
+	}
+	if fetches.Load() != 2 {
+		t.Errorf("expected 2 fetches, got %d", fetches.Load())
+	}
+}
+
+func TestQueryPagesPrefetchStopsEarly(t *testing.T) {
+	var fetches atomic.Int32
+	q := QueryPages(0, numberPages(1000, 10, &fetches), PageOptions{Prefetch: 2})
+	result := Slice(q.Take(5))
+
+	if len(result) != 5 {
+		t.Errorf("expected length 5, got %d", len(result))
+	}
+	// One page consumed, at most one buffered and one in flight
+	if fetches.Load() > 3 {
+		t.Errorf("expected at most 3 fetches, got %d", fetches.Load())
+	}
+	time.Sleep(10 * time.Millisecond)
+	if n := fetches.Load(); n > 3 {
+		t.Errorf("expected prefetching to stop, got %d fetches", n)
+	}
+}
+
+func TestQueryPagesPrefetchesConcurrently(t *testing.T) {
+	fetched := make(chan int, 10)
+	fetch := func(ctx context.Context, page int) ([]int, int, error) {
+		fetched <- page
+		if page == 3 {
+			return []int{page}, 0, nil
+		}
+		return []int{page}, page + 1, nil
+	}
+	q := QueryPages(1, fetch, PageOptions{Prefetch: 1})
+
+	r := newRun(context.Background())
+	defer r.close()
+	it := q.iterate(r)
+	if item, ok := it(); !ok || item != 1 {
+		t.Fatalf("expected 1, got %d", item)
+	}
+	// Page 2 is fetched while the consumer holds page 1
+	if page := <-fetched; page != 1 {
+		t.Errorf("expected page 1 fetched first, got %d", page)
+	}
+	select {
+	case page := <-fetched:
+		if page != 2 {
+			t.Errorf("expected page 2 prefetched, got %d", page)
+		}
+	case <-time.After(time.Second):
+		t.Error("expected next page to be prefetched")
+	}
+	// Page 3 waits until the consumer takes page 2
+	select {
+	case page := <-fetched:
+		t.Errorf("expected only one page ahead, got page %d", page)
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+func TestQueryPagesError(t *testing.T) {
+	fetchErr := errors.New("fetch failed")
+	for _, prefetch := range []int{0, 2} {
+		fetch := func(ctx context.Context, page int) ([]string, int, error) {
+			if page == 2 {
+				return nil, 0, fetchErr
+			}
+			return []string{"a", "b"}, page + 1, nil
+		}
+		result, err := SliceErr(QueryPages(1, fetch, PageOptions{Prefetch: prefetch}))
+
+		if err != fetchErr {
+			t.Errorf("prefetch %d: expected %v, got %v", prefetch, fetchErr, err)
+		}
+		if len(result) != 2 {
+			t.Errorf("prefetch %d: expected length 2, got %d", prefetch, len(result))
+		}
+	}
+}
+
+func TestQueryPagesPrefetchPanic(t *testing.T) {
+	fetch := func(ctx context.Context, page int) ([]int, int, error) {
+		if page == 2 {
+			panic("boom")
+		}
+		return []int{page}, page + 1, nil
+	}
+
+	var result []int
+	func() {
+		defer func() {
+			if p := recover(); p != "boom" {
+				t.Errorf("expected panic boom, got %v", p)
+			}
+		}()
+		for item := range QueryPages(1, fetch, PageOptions{Prefetch: 2}).All() {
+			result = append(result, item)
+		}
+	}()
+	// Pages fetched before the panic are still delivered
+	if len(result) != 1 || result[0] != 1 {
+		t.Errorf("expected [1], got %v", result)
+	}
+}
+
+func TestQueryPagesCancelled(t *testing.T) {
+	fetch := func(ctx context.Context, page int) ([]int, int, error) {
+		select {
+		case <-ctx.Done():
+			return nil, 0, ctx.Err()
+		case <-time.After(time.Millisecond):
+			return []int{page}, page + 1, nil
+		}
+	}
+	for _, prefetch := range []int{0, 2} {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		_, err := SliceCtx(ctx, QueryPages(1, fetch, PageOptions{Prefetch: prefetch}))
+		cancel()
+
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("prefetch %d: expected %v, got %v", prefetch, context.DeadlineExceeded, err)
+		}
+	}
+}
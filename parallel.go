@@ -6,15 +6,12 @@ import (
 )
 
 // Parallel processes items in parallel with a maximum of n concurrent operations.
-// Returns the first error encountered, or nil if all operations succeed.
+// Items are pulled from the query as workers become free, so it is never fully materialized.
+// Returns the first error encountered, including errors reported by the query chain,
+// or nil if all operations succeed.
 func Parallel[T any](
 	ctx context.Context, q *KKQuery[T], n int, fn func(context.Context, T) error,
 ) error {
-	items := Slice(q)
-	if len(items) == 0 {
-		return nil
-	}
-
 	// Create a context that can be cancelled on first error
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
@@ -26,8 +23,12 @@ func Parallel[T any](
 	var firstErr error
 	var errOnce sync.Once
 
+	r := newRun(ctx)
+	defer r.close()
+	iter := q.iterate(r)
+
 loop:
-	for _, item := range items {
+	for {
 		// Check if context is cancelled
 		select {
 		case <-ctx.Done():
@@ -35,13 +36,19 @@ loop:
 		default:
 		}
 
-		// Acquire semaphore
+		// Acquire semaphore before pulling so upstream is not read ahead
 		select {
 		case sem <- struct{}{}:
 		case <-ctx.Done():
 			break loop
 		}
 
+		item, ok := iter()
+		if !ok {
+			<-sem
+			break
+		}
+
 		wg.Add(1)
 		go func(item T) {
 			defer wg.Done()
@@ -67,12 +74,16 @@ loop:
 
 	wg.Wait()
 
-	// Check if context was cancelled before we started
-	if firstErr == nil && ctx.Err() != nil {
-		return ctx.Err()
+	if firstErr != nil {
+		return firstErr
 	}
 
-	return firstErr
+	// Errors reported by the query chain itself
+	if err := r.err(); err != nil {
+		return err
+	}
+
+	return ctx.Err()
 }
 
 // ParallelResult processes items in parallel and collects results.
@@ -81,11 +92,6 @@ loop:
 func ParallelResult[T any, R any](
 	ctx context.Context, q *KKQuery[T], n int, fn func(context.Context, T) (R, error),
 ) ([]R, error) {
-	items := Slice(q)
-	if len(items) == 0 {
-		return nil, nil
-	}
-
 	// Create a context that can be cancelled on first error
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
@@ -98,11 +104,15 @@ func ParallelResult[T any, R any](
 	var errOnce sync.Once
 	var mu sync.Mutex
 
-	// Pre-allocate results slice to maintain order
-	results := make([]R, len(items))
+	// Results grow as items are pulled; each worker writes its own index to maintain order
+	var results []R
+
+	r := newRun(ctx)
+	defer r.close()
+	iter := q.iterate(r)
 
 loop:
-	for i, item := range items {
+	for i := 0; ; i++ {
 		// Check if context is cancelled
 		select {
 		case <-ctx.Done():
@@ -110,13 +120,24 @@ loop:
 		default:
 		}
 
-		// Acquire semaphore
+		// Acquire semaphore before pulling so upstream is not read ahead
 		select {
 		case sem <- struct{}{}:
 		case <-ctx.Done():
 			break loop
 		}
 
+		item, ok := iter()
+		if !ok {
+			<-sem
+			break
+		}
+
+		mu.Lock()
+		var zero R
+		results = append(results, zero)
+		mu.Unlock()
+
 		wg.Add(1)
 		go func(idx int, item T) {
 			defer wg.Done()
@@ -148,15 +169,19 @@ loop:
 
 	wg.Wait()
 
-	// Check if context was cancelled before we started
-	if firstErr == nil && ctx.Err() != nil {
-		return nil, ctx.Err()
-	}
-
 	if firstErr != nil {
 		return nil, firstErr
 	}
 
+	// Errors reported by the query chain itself
+	if err := r.err(); err != nil {
+		return nil, err
+	}
+
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
 	return results, nil
 }
 
@@ -168,11 +193,6 @@ func ParallelByKey[T any, K comparable](
 	ctx context.Context, q *KKQuery[T], n int, perKey int, keyFn func(T) K,
 	fn func(context.Context, T) error,
 ) error {
-	items := Slice(q)
-	if len(items) == 0 {
-		return nil
-	}
-
 	// Create a context that can be cancelled on first error
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
@@ -199,8 +219,12 @@ func ParallelByKey[T any, K comparable](
 	var firstErr error
 	var errOnce sync.Once
 
+	r := newRun(ctx)
+	defer r.close()
+	iter := q.iterate(r)
+
 loop:
-	for _, item := range items {
+	for {
 		// Check if context is cancelled
 		select {
 		case <-ctx.Done():
@@ -208,16 +232,22 @@ loop:
 		default:
 		}
 
-		key := keyFn(item)
-		keySem := getKeySem(key)
-
-		// Acquire global semaphore
+		// Acquire global semaphore before pulling so upstream is not read ahead
 		select {
 		case globalSem <- struct{}{}:
 		case <-ctx.Done():
 			break loop
 		}
 
+		item, ok := iter()
+		if !ok {
+			<-globalSem
+			break
+		}
+
+		key := keyFn(item)
+		keySem := getKeySem(key)
+
 		// Acquire per-key semaphore
 		select {
 		case keySem <- struct{}{}:
@@ -254,12 +284,16 @@ loop:
 
 	wg.Wait()
 
-	// Check if context was cancelled before we started
-	if firstErr == nil && ctx.Err() != nil {
-		return ctx.Err()
+	if firstErr != nil {
+		return firstErr
 	}
 
-	return firstErr
+	// Errors reported by the query chain itself
+	if err := r.err(); err != nil {
+		return err
+	}
+
+	return ctx.Err()
 }
 
 // ParallelByBatch processes items in batches with parallel batch execution.
@@ -268,12 +302,6 @@ loop:
 func ParallelByBatch[T any](
 	ctx context.Context, q *KKQuery[T], batchSize int, n int, fn func(context.Context, []T) error,
 ) error {
-	// Create batches using Chunk
-	batches := Slice(Chunk(q, batchSize))
-	if len(batches) == 0 {
-		return nil
-	}
-
 	// Create a context that can be cancelled on first error
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
@@ -285,8 +313,13 @@ func ParallelByBatch[T any](
 	var firstErr error
 	var errOnce sync.Once
 
+	// Create batches lazily using Chunk
+	r := newRun(ctx)
+	defer r.close()
+	iter := Chunk(q, batchSize).iterate(r)
+
 loop:
-	for _, batch := range batches {
+	for {
 		// Check if context is cancelled
 		select {
 		case <-ctx.Done():
@@ -294,13 +327,19 @@ loop:
 		default:
 		}
 
-		// Acquire semaphore
+		// Acquire semaphore before pulling so upstream is not read ahead
 		select {
 		case sem <- struct{}{}:
 		case <-ctx.Done():
 			break loop
 		}
 
+		batch, ok := iter()
+		if !ok {
+			<-sem
+			break
+		}
+
 		wg.Add(1)
 		go func(batch []T) {
 			defer wg.Done()
@@ -326,12 +365,16 @@ loop:
 
 	wg.Wait()
 
-	// Check if context was cancelled before we started
-	if firstErr == nil && ctx.Err() != nil {
-		return ctx.Err()
+	if firstErr != nil {
+		return firstErr
 	}
 
-	return firstErr
+	// Errors reported by the query chain itself
+	if err := r.err(); err != nil {
+		return err
+	}
+
+	return ctx.Err()
 }
 
 // ParallelByBatchChan collects batches from a channel on-the-fly and processes
@@ -741,3 +741,109 @@ func TestParallelByBatchChanStreaming(t *testing.T) {
 		t.Errorf("expected 2 batches, got %d", batchCount.Load())
 	}
 }
+
+func TestParallelQueryError(t *testing.T) {
+	expectedErr := errors.New("test error")
+	var pulled atomic.Int32
+	q := MappedErr(
+		Query([]int{1, 2, 3, 4, 5, 6}), func(n int) (int, error) {
+			pulled.Add(1)
+			if n == 3 {
+				return 0, expectedErr
+			}
+			return n, nil
+		},
+	)
+	var processed atomic.Int32
+
+	err := Parallel(
+		context.Background(), q, 2, func(ctx context.Context, n int) error {
+			processed.Add(1)
+			return nil
+		},
+	)
+
+	if err != expectedErr {
+		t.Errorf("expected %v, got %v", expectedErr, err)
+	}
+	if pulled.Load() != 3 {
+		t.Errorf("expected 3 items pulled, got %d", pulled.Load())
+	}
+	if processed.Load() != 2 {
+		t.Errorf("expected 2 items processed, got %d", processed.Load())
+	}
+}
+
+func TestParallelResultQueryError(t *testing.T) {
+	expectedErr := errors.New("test error")
+	q := MappedErr(
+		Query([]int{1, 2, 3}), func(n int) (int, error) {
+			if n == 2 {
+				return 0, expectedErr
+			}
+			return n, nil
+		},
+	)
+
+	results, err := ParallelResult(
+		context.Background(), q, 2, func(ctx context.Context, n int) (int, error) {
+			return n, nil
+		},
+	)
+
+	if err != expectedErr {
+		t.Errorf("expected %v, got %v", expectedErr, err)
+	}
+	if results != nil {
+		t.Errorf("expected nil results, got %v", results)
+	}
+}
+
+func TestParallelByBatchStreams(t *testing.T) {
+	var pulled atomic.Int32
+	q := Mapped(
+		Query([]int{1, 2, 3, 4, 5, 6, 7, 8}), func(n int) int {
+			pulled.Add(1)
+			return n
+		},
+	)
+	expectedErr := errors.New("test error")
+
+	err := ParallelByBatch(
+		context.Background(), q, 2, 1, func(ctx context.Context, batch []int) error {
+			return expectedErr
+		},
+	)
+
+	if err != expectedErr {
+		t.Errorf("expected %v, got %v", expectedErr, err)
+	}
+	// The first batch fails; at most one more batch is pulled while it runs
+	if pulled.Load() > 4 {
+		t.Errorf("expected at most 4 items pulled, got %d", pulled.Load())
+	}
+}
+
+func TestParallelCancelsChannelSource(t *testing.T) {
+	ch := make(chan int) // never closed
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- Parallel(
+			ctx, QueryChan(ch), 2, func(ctx context.Context, n int) error {
+				return nil
+			},
+		)
+	}()
+
+	select {
+	case err := <-done:
+		if err != context.DeadlineExceeded {
+			t.Errorf("expected %v, got %v", context.DeadlineExceeded, err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Parallel did not return after context cancellation")
+	}
+}
@@ -1,8 +1,14 @@
 package kk
 
+import (
+	"cmp"
+	"context"
+	"slices"
+)
+
 // KKQuery represents a lazy sequence of items that can be filtered, transformed, and executed.
 type KKQuery[T any] struct {
-	iterate func() Iterator[T]
+	iterate func(r *run) Iterator[T]
 }
 
 // Iterator represents a function that returns the next item and whether there are more items.
@@ -11,7 +17,7 @@ type Iterator[T any] func() (T, bool)
 // Query creates a KKQuery from a slice.
 func Query[T any](slice []T) *KKQuery[T] {
 	return &KKQuery[T]{
-		iterate: func() Iterator[T] {
+		iterate: func(r *run) Iterator[T] {
 			index := 0
 			return func() (T, bool) {
 				if index >= len(slice) {
@@ -27,12 +33,19 @@ func Query[T any](slice []T) *KKQuery[T] {
 }
 
 // QueryChan creates a KKQuery from a channel.
+// A pending receive is interrupted when the terminal's context is cancelled.
 func QueryChan[T any](ch <-chan T) *KKQuery[T] {
 	return &KKQuery[T]{
-		iterate: func() Iterator[T] {
+		iterate: func(r *run) Iterator[T] {
 			return func() (T, bool) {
-				item, ok := <-ch
-				return item, ok
+				select {
+				case item, ok := <-ch:
+					return item, ok
+				case <-r.ctx.Done():
+					r.done()
+					var zero T
+					return zero, false
+				}
 			}
 		},
 	}
@@ -41,7 +54,7 @@ func QueryChan[T any](ch <-chan T) *KKQuery[T] {
 // QueryMapKeys creates a KKQuery from the keys of a map.
 func QueryMapKeys[K comparable, V any](m map[K]V) *KKQuery[K] {
 	return &KKQuery[K]{
-		iterate: func() Iterator[K] {
+		iterate: func(r *run) Iterator[K] {
 			keys := make([]K, 0, len(m))
 			for k := range m {
 				keys = append(keys, k)
@@ -60,16 +73,87 @@ func QueryMapKeys[K comparable, V any](m map[K]V) *KKQuery[K] {
 	}
 }
 
-// Slice materializes the query to a slice.
-func Slice[T any](q *KKQuery[T]) []T {
-	var result []T
-	iter := q.iterate()
-	for {
-		item, ok := iter()
-		if !ok {
-			break
+// QueryMapValues creates a KKQuery from the values of a map.
+func QueryMapValues[K comparable, V any](m map[K]V) *KKQuery[V] {
+	return Mapped(QueryMapEntries(m), func(e KeyValue[K, V]) V { return e.Value })
+}
+
+// QueryMapEntries creates a KKQuery from the key/value pairs of a map.
+func QueryMapEntries[K comparable, V any](m map[K]V) *KKQuery[KeyValue[K, V]] {
+	return &KKQuery[KeyValue[K, V]]{
+		iterate: func(r *run) Iterator[KeyValue[K, V]] {
+			return sliceIterator(mapEntries(m))
+		},
+	}
+}
+
+// QueryMapKeysSorted creates a KKQuery from the keys of a map in ascending order.
+func QueryMapKeysSorted[K cmp.Ordered, V any](m map[K]V) *KKQuery[K] {
+	return Mapped(QueryMapEntriesSorted(m), func(e KeyValue[K, V]) K { return e.Key })
+}
+
+// QueryMapValuesSorted creates a KKQuery from the values of a map, ordered by ascending key.
+func QueryMapValuesSorted[K cmp.Ordered, V any](m map[K]V) *KKQuery[V] {
+	return Mapped(QueryMapEntriesSorted(m), func(e KeyValue[K, V]) V { return e.Value })
+}
+
+// QueryMapEntriesSorted creates a KKQuery from the key/value pairs of a map,
+// ordered by ascending key.
+func QueryMapEntriesSorted[K cmp.Ordered, V any](m map[K]V) *KKQuery[KeyValue[K, V]] {
+	return &KKQuery[KeyValue[K, V]]{
+		iterate: func(r *run) Iterator[KeyValue[K, V]] {
+			entries := mapEntries(m)
+			slices.SortFunc(
+				entries, func(a, b KeyValue[K, V]) int {
+					return cmp.Compare(a.Key, b.Key)
+				},
+			)
+			return sliceIterator(entries)
+		},
+	}
+}
+
+// mapEntries copies the key/value pairs of a map into a slice.
+func mapEntries[K comparable, V any](m map[K]V) []KeyValue[K, V] {
+	entries := make([]KeyValue[K, V], 0, len(m))
+	for k, v := range m {
+		entries = append(entries, KeyValue[K, V]{Key: k, Value: v})
+	}
+	return entries
+}
+
+// sliceIterator returns an iterator over the items of a slice.
+func sliceIterator[T any](items []T) Iterator[T] {
+	index := 0
+	return func() (T, bool) {
+		if index >= len(items) {
+			var zero T
+			return zero, false
 		}
-		result = append(result, item)
+		item := items[index]
+		index++
+		return item, true
 	}
-	return result
+}
+
+// Slice materializes the query to a slice.
+func Slice[T any](q *KKQuery[T]) []T {
+	r := newRun(context.Background())
+	defer r.close()
+	return collect(r, q)
+}
+
+// SliceErr materializes the query to a slice and returns the error reported
+// by the query chain, if any. Items collected before the error are returned.
+func SliceErr[T any](q *KKQuery[T]) ([]T, error) {
+	return SliceCtx(context.Background(), q)
+}
+
+// SliceCtx materializes the query to a slice, stopping with ctx.Err() if ctx
+// is cancelled. Items collected before the error are returned.
+func SliceCtx[T any](ctx context.Context, q *KKQuery[T]) ([]T, error) {
+	r := newRun(ctx)
+	defer r.close()
+	result := collect(r, q)
+	return result, r.err()
 }
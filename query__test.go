@@ -1,7 +1,10 @@
 package kk
 
 import (
+	"context"
+	"slices"
 	"testing"
+	"time"
 )
 
 func TestFrom(t *testing.T) {
@@ -93,3 +96,109 @@ func TestQueryMapKeysEmpty(t *testing.T) {
 		t.Errorf("expected empty slice, got %v", result)
 	}
 }
+
+func TestSliceErrNoError(t *testing.T) {
+	result, err := SliceErr(Query([]int{1, 2, 3}))
+
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if len(result) != 3 {
+		t.Errorf("expected length 3, got %d", len(result))
+	}
+}
+
+func TestSliceCtxCancelsChannelReceive(t *testing.T) {
+	ch := make(chan int)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go func() {
+		ch <- 1
+		ch <- 2
+		cancel()
+	}()
+
+	result, err := SliceCtx(ctx, QueryChan(ch))
+	if err != context.Canceled {
+		t.Errorf("expected %v, got %v", context.Canceled, err)
+	}
+	if len(result) != 2 {
+		t.Errorf("expected length 2, got %d", len(result))
+	}
+}
+
+func TestSliceCtxAlreadyCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	result, err := SliceCtx(ctx, Query([]int{1, 2, 3}))
+	if err != context.Canceled {
+		t.Errorf("expected %v, got %v", context.Canceled, err)
+	}
+	if len(result) != 0 {
+		t.Errorf("expected empty slice, got %v", result)
+	}
+}
+
+func TestSliceCtxCancelIgnoresCollectPolicy(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := SliceCtx(ctx, QueryChan(make(chan int)).OnError(CollectErrors))
+	if err != context.DeadlineExceeded {
+		t.Errorf("expected %v, got %v", context.DeadlineExceeded, err)
+	}
+}
+
+func TestQueryMapValues(t *testing.T) {
+	m := map[string]int{"a": 1, "b": 2, "c": 3}
+	result := Slice(QueryMapValues(m))
+
+	if len(result) != 3 {
+		t.Fatalf("expected length 3, got %d", len(result))
+	}
+	if Sum(QueryMapValues(m), func(n int) int { return n }) != 6 {
+		t.Errorf("expected values to sum to 6, got %v", result)
+	}
+}
+
+func TestQueryMapEntries(t *testing.T) {
+	m := map[string]int{"a": 1, "b": 2, "c": 3}
+	result := Slice(QueryMapEntries(m))
+
+	if len(result) != 3 {
+		t.Fatalf("expected length 3, got %d", len(result))
+	}
+	for _, e := range result {
+		if m[e.Key] != e.Value {
+			t.Errorf("key %q: expected %d, got %d", e.Key, m[e.Key], e.Value)
+		}
+	}
+}
+
+func TestQueryMapSorted(t *testing.T) {
+	m := map[string]int{"c": 3, "a": 1, "d": 4, "b": 2}
+
+	keys := Slice(QueryMapKeysSorted(m))
+	if !slices.Equal(keys, []string{"a", "b", "c", "d"}) {
+		t.Errorf("expected sorted keys, got %v", keys)
+	}
+
+	values := Slice(QueryMapValuesSorted(m))
+	if !slices.Equal(values, []int{1, 2, 3, 4}) {
+		t.Errorf("expected values in key order, got %v", values)
+	}
+
+	entries := Slice(QueryMapEntriesSorted(m))
+	for i, e := range entries {
+		if e.Key != keys[i] || e.Value != values[i] {
+			t.Errorf("index %d: expected %s=%d, got %s=%d", i, keys[i], values[i], e.Key, e.Value)
+		}
+	}
+}
+
+func TestQueryMapSortedEmpty(t *testing.T) {
+	if Count(QueryMapEntriesSorted(map[int]string{})) != 0 {
+		t.Error("expected empty query")
+	}
+}
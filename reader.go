@@ -0,0 +1,69 @@
+package kk
+
+import (
+	"bufio"
+	"io"
+)
+
+// ReaderOptions configures how QueryReader splits its input.
+// The zero value splits lines with the default bufio token size.
+type ReaderOptions struct {
+	// Split is the split function used to tokenize the input.
+	// Defaults to bufio.ScanLines.
+	Split bufio.SplitFunc
+	// MaxTokenSize is the largest token that can be read.
+	// Defaults to bufio.MaxScanTokenSize.
+	MaxTokenSize int
+}
+
+// QueryLines creates a KKQuery of the lines read from rd.
+// It is shorthand for QueryReader with the default options.
+func QueryLines(rd io.Reader) *KKQuery[string] {
+	return QueryReader(rd, ReaderOptions{})
+}
+
+// QueryReader creates a KKQuery of the tokens read from rd.
+// Read errors, including tokens longer than MaxTokenSize, are reported to
+// the terminal operation instead of silently ending the sequence.
+// If rd is an io.Closer it is closed when iteration ends, including on early stop.
+// The reader can only be consumed once; use Cached to iterate more than once.
+func QueryReader(rd io.Reader, opts ReaderOptions) *KKQuery[string] {
+	return &KKQuery[string]{
+		iterate: func(r *run) Iterator[string] {
+			if c, ok := rd.(io.Closer); ok {
+				r.onClose(func() { _ = c.Close() })
+			}
+			scanner := newScanner(rd, opts)
+			done := false
+			return func() (string, bool) {
+				if done || r.done() {
+					return "", false
+				}
+				if scanner.Scan() {
+					return scanner.Text(), true
+				}
+				done = true
+				if err := scanner.Err(); err != nil {
+					r.fail(err)
+				}
+				return "", false
+			}
+		},
+	}
+}
+
+// newScanner creates a bufio.Scanner configured by opts.
+func newScanner(rd io.Reader, opts ReaderOptions) *bufio.Scanner {
+	scanner := bufio.NewScanner(rd)
+	if opts.Split != nil {
+		scanner.Split(opts.Split)
+	}
+	if opts.MaxTokenSize > 0 {
+		initial := 4096
+		if opts.MaxTokenSize < initial {
+			initial = opts.MaxTokenSize
+		}
+		scanner.Buffer(make([]byte, 0, initial), opts.MaxTokenSize)
+	}
+	return scanner
+}
@@ -0,0 +1,106 @@
+package kk
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"testing/iotest"
+)
+
+type closeTracker struct {
+	io.Reader
+	closed int
+}
+
+func (c *closeTracker) Close() error {
+	c.closed++
+	return nil
+}
+
+func TestQueryLines(t *testing.T) {
+	q := QueryLines(strings.NewReader("alpha\nbeta\n\ngamma"))
+	result, err := SliceErr(q)
+
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	expected := []string{"alpha", "beta", "", "gamma"}
+	if len(result) != len(expected) {
+		t.Fatalf("expected length %d, got %d", len(expected), len(result))
+	}
+	for i, v := range result {
+		if v != expected[i] {
+			t.Errorf("index %d: expected %q, got %q", i, expected[i], v)
+		}
+	}
+}
+
+func TestQueryReaderSplit(t *testing.T) {
+	q := QueryReader(strings.NewReader("one two  three\nfour"), ReaderOptions{Split: bufio.ScanWords})
+	result := Slice(q)
+
+	if len(result) != 4 || result[2] != "three" {
+		t.Errorf("expected 4 words, got %v", result)
+	}
+}
+
+func TestQueryReaderMaxTokenSize(t *testing.T) {
+	q := QueryReader(strings.NewReader("short\nthis line is too long\n"), ReaderOptions{MaxTokenSize: 8})
+	result, err := SliceErr(q)
+
+	if !errors.Is(err, bufio.ErrTooLong) {
+		t.Errorf("expected %v, got %v", bufio.ErrTooLong, err)
+	}
+	if len(result) != 1 || result[0] != "short" {
+		t.Errorf("expected [short], got %v", result)
+	}
+}
+
+func TestQueryReaderError(t *testing.T) {
+	readErr := errors.New("read failed")
+	rd := io.MultiReader(strings.NewReader("a\nb\n"), iotest.ErrReader(readErr))
+	result, err := SliceErr(QueryLines(rd))
+
+	if err != readErr {
+		t.Errorf("expected %v, got %v", readErr, err)
+	}
+	if len(result) != 2 {
+		t.Errorf("expected length 2, got %d", len(result))
+	}
+}
+
+func TestQueryReaderClosesOnEarlyStop(t *testing.T) {
+	rd := &closeTracker{Reader: strings.NewReader("a\nb\nc\n")}
+	result := Slice(QueryLines(rd).Take(1))
+
+	if len(result) != 1 {
+		t.Errorf("expected length 1, got %d", len(result))
+	}
+	if rd.closed != 1 {
+		t.Errorf("expected reader closed once, got %d", rd.closed)
+	}
+}
+
+func TestQueryReaderParallel(t *testing.T) {
+	rd := strings.NewReader("1\n2\n3\n4\n5\n")
+	var count atomic.Int32
+
+	err := Parallel(
+		context.Background(), QueryLines(rd).Where(func(s string) bool { return s != "3" }), 2,
+		func(ctx context.Context, line string) error {
+			count.Add(1)
+			return nil
+		},
+	)
+
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if count.Load() != 4 {
+		t.Errorf("expected count 4, got %d", count.Load())
+	}
+}
@@ -0,0 +1,127 @@
+package kk
+
+import (
+	"context"
+	"errors"
+)
+
+// run holds the state shared by every stage of a query chain during a single
+// iteration. Each terminal operation starts a new run and passes it down to
+// the source, so stages can report errors to the terminal and observe
+// cancellation of the terminal's context. Sources register cleanup on the
+// run, and the terminal closes it when iteration ends for any reason.
+// A run is only used by the goroutine that iterates the chain.
+type run struct {
+	*runState
+	cleanups []func()
+}
+
+// runState is the part of a run shared with its nested scopes.
+type runState struct {
+	ctx       context.Context
+	policy    ErrorPolicy
+	policySet bool
+	errs      []error
+	stopped   bool
+}
+
+// newRun creates the state for a new iteration bound to ctx.
+func newRun(ctx context.Context) *run {
+	return &run{runState: &runState{ctx: ctx}}
+}
+
+// detached creates a run with the same error policy that is bound to ctx
+// instead of the terminal's context, for stages that iterate upstream in
+// another goroutine or beyond a single iteration.
+func (r *run) detached(ctx context.Context) *run {
+	return &run{runState: &runState{ctx: ctx, policy: r.policy, policySet: r.policySet}}
+}
+
+// scope creates a run that shares r's context, errors and stop state but has
+// its own cleanups. Stages that finish a nested query close its scope to
+// release that query's resources without waiting for the whole run to end,
+// and without touching cleanups registered by other stages.
+func (r *run) scope() *run {
+	return &run{runState: r.runState}
+}
+
+// fail records err and reports whether iteration should continue past the
+// item that caused it. Under StopOnError the run is stopped and later stages
+// see the end of the sequence.
+func (r *run) fail(err error) bool {
+	return r.failWith(r.policy, err)
+}
+
+// failWith is like fail but applies policy instead of the chain's policy.
+// Sources with their own error handling options use it.
+func (r *run) failWith(policy ErrorPolicy, err error) bool {
+	switch policy {
+	case SkipErrors:
+		return true
+	case CollectErrors:
+		r.errs = append(r.errs, err)
+		return true
+	default:
+		r.errs = append(r.errs, err)
+		r.stopped = true
+		return false
+	}
+}
+
+// done reports whether the run should end, either because it was already
+// stopped or because its context is done. Cancellation always stops the run,
+// whatever the ErrorPolicy, and ctx.Err() is reported to the terminal.
+func (r *run) done() bool {
+	if r.stopped {
+		return true
+	}
+	if err := r.ctx.Err(); err != nil {
+		r.errs = append(r.errs, err)
+		r.stopped = true
+		return true
+	}
+	return false
+}
+
+// onClose registers fn to run when the run is closed.
+func (r *run) onClose(fn func()) {
+	r.cleanups = append(r.cleanups, fn)
+}
+
+// close runs every registered cleanup exactly once, most recent first, and
+// forgets them. Terminals defer it so cleanup also happens on early stop and
+// panic. Every cleanup runs even if an earlier one panics.
+func (r *run) close() {
+	fns := r.cleanups
+	r.cleanups = nil
+	for _, fn := range fns {
+		defer fn()
+	}
+}
+
+// err returns the errors recorded during the run, or nil if there were none.
+func (r *run) err() error {
+	switch len(r.errs) {
+	case 0:
+		return nil
+	case 1:
+		return r.errs[0]
+	default:
+		return errors.Join(r.errs...)
+	}
+}
+
+// collect materializes a query within an existing run, stopping early if
+// the run's context is cancelled.
+func collect[T any](r *run, q *KKQuery[T]) []T {
+	var result []T
+	iter := q.iterate(r)
+	for !r.done() {
+		item, ok := iter()
+		if !ok {
+			break
+		}
+		result = append(result, item)
+	}
+	return result
+}
@@ -0,0 +1,137 @@
+package kk
+
+import (
+	"context"
+	"iter"
+)
+
+// KeyValue holds a key and its associated value.
+type KeyValue[K any, V any] struct {
+	Key   K
+	Value V
+}
+
+// QuerySeq creates a KKQuery from a standard library iterator.
+// If iteration stops early, the sequence is stopped as if its loop broke.
+func QuerySeq[T any](seq iter.Seq[T]) *KKQuery[T] {
+	return &KKQuery[T]{
+		iterate: func(r *run) Iterator[T] {
+			next, stop := iter.Pull(seq)
+			r.onClose(stop)
+			done := false
+			return func() (T, bool) {
+				if done {
+					var zero T
+					return zero, false
+				}
+				item, ok := next()
+				if !ok {
+					done = true
+					stop()
+				}
+				return item, ok
+			}
+		},
+	}
+}
+
+// QuerySeq2 creates a KKQuery of key/value pairs from a standard library iterator.
+func QuerySeq2[K any, V any](seq iter.Seq2[K, V]) *KKQuery[KeyValue[K, V]] {
+	return &KKQuery[KeyValue[K, V]]{
+		iterate: func(r *run) Iterator[KeyValue[K, V]] {
+			next, stop := iter.Pull2(seq)
+			r.onClose(stop)
+			done := false
+			return func() (KeyValue[K, V], bool) {
+				if done {
+					return KeyValue[K, V]{}, false
+				}
+				k, v, ok := next()
+				if !ok {
+					done = true
+					stop()
+					return KeyValue[K, V]{}, false
+				}
+				return KeyValue[K, V]{Key: k, Value: v}, true
+			}
+		},
+	}
+}
+
+// All returns the query as a standard library iterator, so it can be used
+// with range loops and functions like slices.Collect.
+// Breaking out of the loop stops pulling from upstream and releases its resources.
+// An error in the chain ends the loop as if the query had no more items, and
+// the error is discarded; use AllErr to see it.
+func (q *KKQuery[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		r := newRun(context.Background())
+		defer r.close()
+		it := q.iterate(r)
+		for {
+			item, ok := it()
+			if !ok {
+				return
+			}
+			if !yield(item) {
+				return
+			}
+		}
+	}
+}
+
+// AllErr is like All but also yields the error reported by the query chain.
+// Items are yielded with a nil error. If the chain reported an error, it is
+// yielded once with the zero value after the last item:
+//
+//	for item, err := range q.AllErr() {
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+func (q *KKQuery[T]) AllErr() iter.Seq2[T, error] {
+	return q.AllCtx(context.Background())
+}
+
+// AllCtx is like AllErr, stopping with ctx.Err() if ctx is cancelled.
+func (q *KKQuery[T]) AllCtx(ctx context.Context) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		r := newRun(ctx)
+		defer r.close()
+		it := q.iterate(r)
+		for !r.done() {
+			item, ok := it()
+			if !ok {
+				break
+			}
+			if !yield(item, nil) {
+				return
+			}
+		}
+		if err := r.err(); err != nil {
+			var zero T
+			yield(zero, err)
+		}
+	}
+}
+
+// Enumerate returns the query as a standard library iterator of index/item pairs.
+// Like All, an error in the chain ends the loop and is discarded; use AllErr
+// to see it.
+func (q *KKQuery[T]) Enumerate() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		r := newRun(context.Background())
+		defer r.close()
+		it := q.iterate(r)
+		for i := 0; ; i++ {
+			item, ok := it()
+			if !ok {
+				return
+			}
+			if !yield(i, item) {
+				return
+			}
+		}
+	}
+}
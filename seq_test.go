@@ -0,0 +1,192 @@
+package kk
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestQuerySeq(t *testing.T) {
+	q := QuerySeq(slices.Values([]int{1, 2, 3, 4})).Where(func(n int) bool { return n%2 == 0 })
+	result := Slice(q)
+
+	if len(result) != 2 || result[0] != 2 || result[1] != 4 {
+		t.Errorf("expected [2 4], got %v", result)
+	}
+
+	// Re-iterating starts the sequence again
+	if Count(q) != 2 {
+		t.Errorf("expected count 2, got %d", Count(q))
+	}
+}
+
+func TestQuerySeqTake(t *testing.T) {
+	pulled := 0
+	seq := func(yield func(int) bool) {
+		for i := 0; ; i++ {
+			pulled++
+			if !yield(i) {
+				return
+			}
+		}
+	}
+
+	result := Slice(QuerySeq(seq).Take(3))
+	if len(result) != 3 {
+		t.Errorf("expected length 3, got %d", len(result))
+	}
+	if pulled != 3 {
+		t.Errorf("expected 3 items pulled, got %d", pulled)
+	}
+}
+
+func TestQuerySeq2(t *testing.T) {
+	m := map[string]int{"a": 1, "b": 2, "c": 3}
+	q := QuerySeq2(maps.All(m))
+	result := Slice(q)
+
+	if len(result) != 3 {
+		t.Fatalf("expected length 3, got %d", len(result))
+	}
+	for _, kv := range result {
+		if m[kv.Key] != kv.Value {
+			t.Errorf("key %s: expected %d, got %d", kv.Key, m[kv.Key], kv.Value)
+		}
+	}
+}
+
+func TestAllRange(t *testing.T) {
+	var result []int
+	for n := range Query([]int{1, 2, 3}).All() {
+		result = append(result, n)
+	}
+
+	if len(result) != 3 || result[0] != 1 || result[2] != 3 {
+		t.Errorf("expected [1 2 3], got %v", result)
+	}
+}
+
+func TestAllBreak(t *testing.T) {
+	var calls int
+	q := Mapped(
+		Query([]int{1, 2, 3, 4, 5}), func(n int) int {
+			calls++
+			return n
+		},
+	)
+
+	for n := range q.All() {
+		if n == 2 {
+			break
+		}
+	}
+
+	if calls != 2 {
+		t.Errorf("expected upstream to run 2 times, got %d", calls)
+	}
+}
+
+func TestAllCollect(t *testing.T) {
+	result := slices.Collect(Query([]string{"a", "b"}).All())
+
+	if !slices.Equal(result, []string{"a", "b"}) {
+		t.Errorf("expected [a b], got %v", result)
+	}
+}
+
+func TestEnumerate(t *testing.T) {
+	for i, s := range Query([]string{"a", "b", "c"}).Enumerate() {
+		expected := []string{"a", "b", "c"}[i]
+		if s != expected {
+			t.Errorf("index %d: expected %s, got %s", i, expected, s)
+		}
+	}
+}
+
+func TestSeqRoundTrip(t *testing.T) {
+	q := QuerySeq(Query([]int{3, 1, 2}).All())
+	result := Slice(SortedBy(q, func(n int) int { return n }).KKQuery)
+
+	if !slices.Equal(result, []int{1, 2, 3}) {
+		t.Errorf("expected [1 2 3], got %v", result)
+	}
+}
+
+func TestAllErr(t *testing.T) {
+	q := MappedErr(Query([]int{1, 2, 3}), failOn(2))
+
+	var items []int
+	var errs []error
+	for item, err := range q.AllErr() {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		items = append(items, item)
+	}
+
+	if !slices.Equal(items, []int{1}) {
+		t.Errorf("expected [1], got %v", items)
+	}
+	if len(errs) != 1 || errs[0].Error() != "bad item 2" {
+		t.Errorf("expected bad item 2 once, got %v", errs)
+	}
+}
+
+func TestAllErrNoError(t *testing.T) {
+	count := 0
+	for _, err := range Query([]int{1, 2, 3}).AllErr() {
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+		count++
+	}
+
+	if count != 3 {
+		t.Errorf("expected 3 items, got %d", count)
+	}
+}
+
+func TestAllErrCollect(t *testing.T) {
+	q := MappedErr(Query([]int{1, 2, 3, 4}), failOn(2)).OnError(CollectErrors)
+
+	var items []int
+	var last error
+	for item, err := range q.AllErr() {
+		if err != nil {
+			last = err
+			continue
+		}
+		items = append(items, item)
+	}
+
+	// Collected errors are yielded after every item
+	if !slices.Equal(items, []int{1, 3, 4}) {
+		t.Errorf("expected [1 3 4], got %v", items)
+	}
+	if last == nil || last.Error() != "bad item 2" {
+		t.Errorf("expected bad item 2, got %v", last)
+	}
+}
+
+func TestAllCtxCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var last error
+	count := 0
+	for _, err := range Generate(func(i int) int { return i }).AllCtx(ctx) {
+		if err != nil {
+			last = err
+			break
+		}
+		count++
+		if count == 3 {
+			cancel()
+		}
+	}
+
+	if count != 3 || last != context.Canceled {
+		t.Errorf("expected 3 items then %v, got %d items and %v", context.Canceled, count, last)
+	}
+}
@@ -3,9 +3,9 @@ package kk
 // Concat combines two queries into one.
 func (q *KKQuery[T]) Concat(other *KKQuery[T]) *KKQuery[T] {
 	return &KKQuery[T]{
-		iterate: func() Iterator[T] {
-			iter1 := q.iterate()
-			iter2 := other.iterate()
+		iterate: func(r *run) Iterator[T] {
+			iter1 := q.iterate(r)
+			iter2 := other.iterate(r)
 			first := true
 			return func() (T, bool) {
 				if first {
@@ -13,6 +13,10 @@ func (q *KKQuery[T]) Concat(other *KKQuery[T]) *KKQuery[T] {
 					if ok {
 						return item, true
 					}
+					if r.stopped {
+						var zero T
+						return zero, false
+					}
 					first = false
 				}
 				return iter2()
@@ -24,17 +28,22 @@ func (q *KKQuery[T]) Concat(other *KKQuery[T]) *KKQuery[T] {
 // Except returns items in the first query that are not in the second.
 func (q *KKQuery[T]) Except(other *KKQuery[T]) *KKQuery[T] {
 	return &KKQuery[T]{
-		iterate: func() Iterator[T] {
+		iterate: func(r *run) Iterator[T] {
 			// Materialize the second query to check membership
 			otherSet := make(map[any]bool)
-			for _, item := range Slice(other) {
+			for _, item := range collect(r, other) {
 				otherSet[item] = true
 			}
 
-			iter := q.iterate()
+			iter := q.iterate(r)
 			seen := make(map[any]bool)
 			return func() (T, bool) {
 				for {
+					// The membership set is incomplete if the run stopped while building it
+					if r.stopped {
+						var zero T
+						return zero, false
+					}
 					item, ok := iter()
 					if !ok {
 						var zero T
@@ -54,17 +63,22 @@ func (q *KKQuery[T]) Except(other *KKQuery[T]) *KKQuery[T] {
 // Intersect returns items that are in both queries (distinct).
 func (q *KKQuery[T]) Intersect(other *KKQuery[T]) *KKQuery[T] {
 	return &KKQuery[T]{
-		iterate: func() Iterator[T] {
+		iterate: func(r *run) Iterator[T] {
 			// Materialize the second query to check membership
 			otherSet := make(map[any]bool)
-			for _, item := range Slice(other) {
+			for _, item := range collect(r, other) {
 				otherSet[item] = true
 			}
 
-			iter := q.iterate()
+			iter := q.iterate(r)
 			seen := make(map[any]bool)
 			return func() (T, bool) {
 				for {
+					// The membership set is incomplete if the run stopped while building it
+					if r.stopped {
+						var zero T
+						return zero, false
+					}
 					item, ok := iter()
 					if !ok {
 						var zero T
@@ -84,9 +98,9 @@ func (q *KKQuery[T]) Intersect(other *KKQuery[T]) *KKQuery[T] {
 // Union returns items that are in either query (distinct).
 func (q *KKQuery[T]) Union(other *KKQuery[T]) *KKQuery[T] {
 	return &KKQuery[T]{
-		iterate: func() Iterator[T] {
-			iter1 := q.iterate()
-			iter2 := other.iterate()
+		iterate: func(r *run) Iterator[T] {
+			iter1 := q.iterate(r)
+			iter2 := other.iterate(r)
 			seen := make(map[any]bool)
 			first := true
 			return func() (T, bool) {
@@ -100,6 +114,10 @@ func (q *KKQuery[T]) Union(other *KKQuery[T]) *KKQuery[T] {
 							}
 							continue
 						}
+						if r.stopped {
+							var zero T
+							return zero, false
+						}
 						first = false
 					}
 					item, ok := iter2()
@@ -0,0 +1,83 @@
+package kk
+
+import (
+	"context"
+	"database/sql"
+)
+
+// SQLQueryer runs a query that returns rows. It is implemented by *sql.DB,
+// *sql.Conn and *sql.Tx.
+type SQLQueryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// QueryRows creates a KKQuery that scans each row of rows into T as it is pulled.
+// The rows are closed when iteration ends, including on early stop, and
+// rows.Err() and scan errors are reported to the terminal operation.
+// Rows can only be consumed once; use QuerySQL for a query that can be re-iterated.
+func QueryRows[T any](rows *sql.Rows, scan func(*sql.Rows) (T, error)) *KKQuery[T] {
+	return &KKQuery[T]{
+		iterate: func(r *run) Iterator[T] {
+			r.onClose(func() { _ = rows.Close() })
+			return rowsIterator(r, rows, scan)
+		},
+	}
+}
+
+// QuerySQL creates a KKQuery that runs query on db and scans each row into T
+// as it is pulled. The query runs when the first item is pulled, once per
+// iteration, with the terminal's context, so cancelling the context cancels
+// the query. Rows are closed when iteration ends, including on early stop.
+func QuerySQL[T any](db SQLQueryer, scan func(*sql.Rows) (T, error), query string, args ...any) *KKQuery[T] {
+	return &KKQuery[T]{
+		iterate: func(r *run) Iterator[T] {
+			var iter Iterator[T]
+			return func() (T, bool) {
+				if iter == nil {
+					if r.done() {
+						var zero T
+						return zero, false
+					}
+					rows, err := db.QueryContext(r.ctx, query, args...)
+					if err != nil {
+						r.fail(err)
+						iter = func() (T, bool) {
+							var zero T
+							return zero, false
+						}
+						return iter()
+					}
+					r.onClose(func() { _ = rows.Close() })
+					iter = rowsIterator(r, rows, scan)
+				}
+				return iter()
+			}
+		},
+	}
+}
+
+// rowsIterator scans rows into T until they are exhausted.
+func rowsIterator[T any](r *run, rows *sql.Rows, scan func(*sql.Rows) (T, error)) Iterator[T] {
+	done := false
+	return func() (T, bool) {
+		var zero T
+		for !done && !r.done() {
+			if !rows.Next() {
+				done = true
+				if err := rows.Err(); err != nil {
+					r.fail(err)
+				}
+				return zero, false
+			}
+			item, err := scan(rows)
+			if err != nil {
+				if r.fail(err) {
+					continue
+				}
+				return zero, false
+			}
+			return item, true
+		}
+		return zero, false
+	}
+}
@@ -0,0 +1,263 @@
+package kk
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// fakeDriver serves a "users" table of n rows from memory. A query of
+// "fail at N" returns rows that fail with errFakeRows after N rows.
+type fakeDriver struct {
+	rows    int
+	queries atomic.Int32
+	closed  atomic.Int32
+}
+
+var errFakeRows = errors.New("fake rows error")
+
+var (
+	fakeDriverMu    sync.Mutex
+	fakeDriverCount int
+)
+
+func openFakeDB(t *testing.T, rows int) (*sql.DB, *fakeDriver) {
+	t.Helper()
+	d := &fakeDriver{rows: rows}
+	fakeDriverMu.Lock()
+	fakeDriverCount++
+	name := "kkfake" + strconv.Itoa(fakeDriverCount)
+	fakeDriverMu.Unlock()
+	sql.Register(name, d)
+
+	db, err := sql.Open(name, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	return db, d
+}
+
+func (d *fakeDriver) Open(string) (driver.Conn, error) {
+	return &fakeConn{d: d}, nil
+}
+
+type fakeConn struct {
+	d *fakeDriver
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{d: c.d, query: query}, nil
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("not supported")
+}
+
+type fakeStmt struct {
+	d     *fakeDriver
+	query string
+}
+
+func (s *fakeStmt) Close() error {
+	return nil
+}
+
+func (s *fakeStmt) NumInput() int {
+	return -1
+}
+
+func (s *fakeStmt) Exec([]driver.Value) (driver.Result, error) {
+	return nil, errors.New("not supported")
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.d.queries.Add(1)
+	failAt := -1
+	if s.query == "fail" {
+		failAt = int(args[0].(int64))
+	}
+	return &fakeRows{d: s.d, n: s.d.rows, failAt: failAt}, nil
+}
+
+type fakeRows struct {
+	d      *fakeDriver
+	n      int
+	i      int
+	failAt int
+}
+
+func (r *fakeRows) Columns() []string {
+	return []string{"id", "name"}
+}
+
+func (r *fakeRows) Close() error {
+	r.d.closed.Add(1)
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.i == r.failAt {
+		return errFakeRows
+	}
+	if r.i >= r.n {
+		return io.EOF
+	}
+	r.i++
+	dest[0] = int64(r.i)
+	dest[1] = "user" + strconv.Itoa(r.i)
+	return nil
+}
+
+type sqlUser struct {
+	ID   int
+	Name string
+}
+
+func scanSQLUser(rows *sql.Rows) (sqlUser, error) {
+	var u sqlUser
+	err := rows.Scan(&u.ID, &u.Name)
+	return u, err
+}
+
+func TestQueryRows(t *testing.T) {
+	db, d := openFakeDB(t, 3)
+	rows, err := db.Query("users")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := SliceErr(QueryRows(rows, scanSQLUser))
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if len(result) != 3 || result[2].ID != 3 || result[2].Name != "user3" {
+		t.Errorf("unexpected rows %v", result)
+	}
+	if d.closed.Load() != 1 {
+		t.Errorf("expected rows closed once, got %d", d.closed.Load())
+	}
+}
+
+func TestQueryRowsEarlyStop(t *testing.T) {
+	db, d := openFakeDB(t, 100)
+	rows, err := db.Query("users")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	u, ok := First(QueryRows(rows, scanSQLUser))
+	if !ok || u.ID != 1 {
+		t.Errorf("expected first user, got %v", u)
+	}
+	if d.closed.Load() != 1 {
+		t.Errorf("expected rows closed once, got %d", d.closed.Load())
+	}
+}
+
+func TestQueryRowsErr(t *testing.T) {
+	db, _ := openFakeDB(t, 5)
+	rows, err := db.Query("fail", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := SliceErr(QueryRows(rows, scanSQLUser))
+	if err != errFakeRows {
+		t.Errorf("expected %v, got %v", errFakeRows, err)
+	}
+	if len(result) != 2 {
+		t.Errorf("expected length 2, got %d", len(result))
+	}
+}
+
+func TestQueryRowsScanError(t *testing.T) {
+	db, _ := openFakeDB(t, 3)
+	rows, err := db.Query("users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	scan := func(rows *sql.Rows) (sqlUser, error) {
+		u, err := scanSQLUser(rows)
+		if err == nil && u.ID == 2 {
+			err = errors.New("bad row")
+		}
+		return u, err
+	}
+
+	result, err := SliceErr(QueryRows(rows, scan).OnError(CollectErrors))
+	if err == nil || err.Error() != "bad row" {
+		t.Errorf("expected bad row, got %v", err)
+	}
+	if len(result) != 2 {
+		t.Errorf("expected length 2, got %d", len(result))
+	}
+}
+
+func TestQuerySQL(t *testing.T) {
+	db, d := openFakeDB(t, 10)
+	q := QuerySQL(db, scanSQLUser, "users")
+
+	if d.queries.Load() != 0 {
+		t.Errorf("expected no query before iteration, got %d", d.queries.Load())
+	}
+	if n := Count(q); n != 10 {
+		t.Errorf("expected count 10, got %d", n)
+	}
+	if n := Count(q.Take(3)); n != 3 {
+		t.Errorf("expected count 3, got %d", n)
+	}
+	if d.queries.Load() != 2 {
+		t.Errorf("expected 2 queries, got %d", d.queries.Load())
+	}
+	if d.closed.Load() != 2 {
+		t.Errorf("expected rows closed twice, got %d", d.closed.Load())
+	}
+}
+
+func TestQuerySQLParallel(t *testing.T) {
+	db, d := openFakeDB(t, 50)
+	var sum atomic.Int64
+
+	err := Parallel(
+		context.Background(), QuerySQL(db, scanSQLUser, "users"), 4,
+		func(ctx context.Context, u sqlUser) error {
+			sum.Add(int64(u.ID))
+			return nil
+		},
+	)
+
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if sum.Load() != 1275 {
+		t.Errorf("expected sum 1275, got %d", sum.Load())
+	}
+	if d.closed.Load() != 1 {
+		t.Errorf("expected rows closed once, got %d", d.closed.Load())
+	}
+}
+
+func TestQuerySQLCancelled(t *testing.T) {
+	db, d := openFakeDB(t, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := SliceCtx(ctx, QuerySQL(db, scanSQLUser, "users"))
+	if err != context.Canceled {
+		t.Errorf("expected %v, got %v", context.Canceled, err)
+	}
+	if d.queries.Load() != 0 {
+		t.Errorf("expected no query, got %d", d.queries.Load())
+	}
+}
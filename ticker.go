@@ -0,0 +1,126 @@
+package kk
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+)
+
+// Clock tells time for time-driven sources. Tests can supply a fake clock.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// realClock is the Clock backed by the time package.
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// TickerOptions configures QueryTicker and QueryPoll.
+type TickerOptions struct {
+	// Jitter adds a random delay in [0, Jitter) to each tick, to spread out
+	// pollers that start together.
+	Jitter time.Duration
+	// Max ends the sequence after this many ticks. Zero means no limit.
+	Max int
+	// Immediate emits the first tick right away instead of after one interval.
+	Immediate bool
+	// Clock is used to tell time. Defaults to the system clock.
+	Clock Clock
+}
+
+// QueryTicker creates a KKQuery that yields the current time every interval.
+// Ticks are scheduled on a fixed grid from the start of iteration. Like
+// time.Ticker, a slow consumer gets one late tick right away and the other
+// missed ticks are dropped rather than delivered in a burst.
+// The sequence is infinite unless Max is set, and ends with ctx.Err() when
+// the terminal's context is cancelled, so it can drive a streaming executor:
+//
+//	err := kk.Parallel(ctx, kk.QueryTicker(time.Minute, kk.TickerOptions{}), 1, pollOnce)
+func QueryTicker(interval time.Duration, opts TickerOptions) *KKQuery[time.Time] {
+	return &KKQuery[time.Time]{
+		iterate: func(r *run) Iterator[time.Time] {
+			clock := opts.Clock
+			if clock == nil {
+				clock = realClock{}
+			}
+			next := clock.Now()
+			if !opts.Immediate {
+				next = next.Add(interval)
+			}
+			count := 0
+			return func() (time.Time, bool) {
+				if opts.Max > 0 && count >= opts.Max {
+					return time.Time{}, false
+				}
+
+				wait := next.Sub(clock.Now())
+				if opts.Jitter > 0 {
+					wait += rand.N(opts.Jitter)
+				}
+				if wait > 0 {
+					select {
+					case <-clock.After(wait):
+					case <-r.ctx.Done():
+						r.done()
+						return time.Time{}, false
+					}
+				} else if r.done() {
+					return time.Time{}, false
+				}
+
+				now := clock.Now()
+				count++
+				for interval > 0 && !next.After(now) {
+					next = next.Add(interval)
+				}
+				return now, true
+			}
+		},
+	}
+}
+
+// QueryPoll creates a KKQuery that calls poll on every tick of a ticker and
+// yields the items it returns. poll receives the terminal's context.
+// Poll errors are reported according to the chain's ErrorPolicy, so with
+// CollectErrors or SkipErrors a failed poll does not end the sequence.
+func QueryPoll[T any](
+	interval time.Duration, poll func(ctx context.Context) ([]T, error), opts TickerOptions,
+) *KKQuery[T] {
+	ticker := QueryTicker(interval, opts)
+	return &KKQuery[T]{
+		iterate: func(r *run) Iterator[T] {
+			ticks := ticker.iterate(r)
+			var items []T
+			index := 0
+			return func() (T, bool) {
+				for index >= len(items) {
+					if _, ok := ticks(); !ok {
+						var zero T
+						return zero, false
+					}
+					polled, err := poll(r.ctx)
+					if err != nil {
+						if r.fail(err) {
+							continue
+						}
+						var zero T
+						return zero, false
+					}
+					items = polled
+					index = 0
+				}
+				item := items[index]
+				index++
+				return item, true
+			}
+		},
+	}
+}
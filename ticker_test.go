@@ -0,0 +1,168 @@
+package kk
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeClock advances instantly: After moves the clock forward by d and fires.
+type fakeClock struct {
+	mu    sync.Mutex
+	now   time.Time
+	waits []time.Duration
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.waits = append(c.waits, d)
+	c.now = c.now.Add(d)
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
+
+func (c *fakeClock) advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func TestQueryTicker(t *testing.T) {
+	clock := newFakeClock()
+	start := clock.Now()
+	result := Slice(QueryTicker(time.Second, TickerOptions{Max: 3, Clock: clock}))
+
+	if len(result) != 3 {
+		t.Fatalf("expected 3 ticks, got %d", len(result))
+	}
+	for i, tick := range result {
+		expected := start.Add(time.Duration(i+1) * time.Second)
+		if !tick.Equal(expected) {
+			t.Errorf("tick %d: expected %v, got %v", i, expected, tick)
+		}
+	}
+}
+
+func TestQueryTickerImmediate(t *testing.T) {
+	clock := newFakeClock()
+	start := clock.Now()
+	result := Slice(QueryTicker(time.Second, TickerOptions{Max: 2, Immediate: true, Clock: clock}))
+
+	if len(result) != 2 || !result[0].Equal(start) || !result[1].Equal(start.Add(time.Second)) {
+		t.Errorf("expected ticks at 0s and 1s, got %v", result)
+	}
+}
+
+func TestQueryTickerDropsMissedTicks(t *testing.T) {
+	clock := newFakeClock()
+	start := clock.Now()
+	q := Mapped(
+		QueryTicker(time.Second, TickerOptions{Max: 3, Clock: clock}), func(tick time.Time) time.Time {
+			// A slow consumer misses the next two ticks
+			clock.advance(2500 * time.Millisecond)
+			return tick
+		},
+	)
+	result := Slice(q)
+
+	// Each late tick is delivered right away; the ticks missed in between are dropped
+	expected := []time.Time{start.Add(time.Second), start.Add(3500 * time.Millisecond), start.Add(6 * time.Second)}
+	for i, tick := range result {
+		if !tick.Equal(expected[i]) {
+			t.Errorf("tick %d: expected %v, got %v", i, expected[i], tick)
+		}
+	}
+}
+
+func TestQueryTickerJitter(t *testing.T) {
+	clock := newFakeClock()
+	Slice(QueryTicker(time.Second, TickerOptions{Max: 20, Jitter: 100 * time.Millisecond, Clock: clock}))
+
+	for i, wait := range clock.waits {
+		if wait < 0 || wait >= 1100*time.Millisecond {
+			t.Errorf("wait %d: expected within [0, 1.1s), got %v", i, wait)
+		}
+	}
+}
+
+func TestQueryTickerCancelled(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	// Real clock: the hour-long wait is interrupted by the context
+	_, err := SliceCtx(ctx, QueryTicker(time.Hour, TickerOptions{}))
+	if err != context.DeadlineExceeded {
+		t.Errorf("expected %v, got %v", context.DeadlineExceeded, err)
+	}
+}
+
+func TestQueryPoll(t *testing.T) {
+	clock := newFakeClock()
+	var polls atomic.Int32
+	poll := func(ctx context.Context) ([]int, error) {
+		n := int(polls.Add(1))
+		if n == 2 {
+			return nil, errors.New("poll failed")
+		}
+		return []int{n, n * 10}, nil
+	}
+
+	q := QueryPoll(time.Second, poll, TickerOptions{Max: 3, Clock: clock}).OnError(CollectErrors)
+	result, err := SliceErr(q)
+
+	if err == nil || err.Error() != "poll failed" {
+		t.Errorf("expected poll failed, got %v", err)
+	}
+	expected := []int{1, 10, 3, 30}
+	if len(result) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, result)
+	}
+	for i, v := range result {
+		if v != expected[i] {
+			t.Errorf("index %d: expected %d, got %d", i, expected[i], v)
+		}
+	}
+}
+
+func TestQueryPollParallel(t *testing.T) {
+	clock := newFakeClock()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	poll := func(ctx context.Context) ([]string, error) {
+		return []string{"job"}, nil
+	}
+	var processed atomic.Int32
+
+	// A polling daemon as one chain, stopped by cancelling its context
+	err := Parallel(
+		ctx, QueryPoll(time.Minute, poll, TickerOptions{Clock: clock}), 2,
+		func(ctx context.Context, job string) error {
+			if processed.Add(1) == 5 {
+				cancel()
+			}
+			return nil
+		},
+	)
+
+	if err != context.Canceled {
+		t.Errorf("expected %v, got %v", context.Canceled, err)
+	}
+	if processed.Load() < 5 {
+		t.Errorf("expected at least 5 jobs processed, got %d", processed.Load())
+	}
+}
@@ -4,8 +4,8 @@ package kk
 // This is a function (not a method) because it returns a different type.
 func Mapped[T any, R any](q *KKQuery[T], fn func(T) R) *KKQuery[R] {
 	return &KKQuery[R]{
-		iterate: func() Iterator[R] {
-			iter := q.iterate()
+		iterate: func(r *run) Iterator[R] {
+			iter := q.iterate(r)
 			return func() (R, bool) {
 				item, ok := iter()
 				if !ok {
@@ -22,8 +22,8 @@ func Mapped[T any, R any](q *KKQuery[T], fn func(T) R) *KKQuery[R] {
 // This is a function (not a method) because it returns a different type.
 func Flattened[T any, R any](q *KKQuery[T], fn func(T) []R) *KKQuery[R] {
 	return &KKQuery[R]{
-		iterate: func() Iterator[R] {
-			iter := q.iterate()
+		iterate: func(r *run) Iterator[R] {
+			iter := q.iterate(r)
 			var current []R
 			index := 0
 			return func() (R, bool) {
@@ -50,3 +50,104 @@ func Flattened[T any, R any](q *KKQuery[T], fn func(T) []R) *KKQuery[R] {
 		},
 	}
 }
+
+// MappedErr transforms each item to a new type with a function that can fail.
+// Errors are reported to the terminal operation according to the chain's ErrorPolicy.
+// This is a function (not a method) because it returns a different type.
+func MappedErr[T any, R any](q *KKQuery[T], fn func(T) (R, error)) *KKQuery[R] {
+	return &KKQuery[R]{
+		iterate: func(r *run) Iterator[R] {
+			iter := q.iterate(r)
+			return func() (R, bool) {
+				for {
+					item, ok := iter()
+					if !ok {
+						var zero R
+						return zero, false
+					}
+					result, err := fn(item)
+					if err != nil {
+						if r.fail(err) {
+							continue
+						}
+						var zero R
+						return zero, false
+					}
+					return result, true
+				}
+			}
+		},
+	}
+}
+
+// FlattenedQuery transforms each item to a query and flattens the results.
+// Each inner query is closed as soon as it is exhausted, so resources it
+// holds do not accumulate until the outer iteration ends.
+// This is a function (not a method) because it returns a different type.
+func FlattenedQuery[T any, R any](q *KKQuery[T], fn func(T) *KKQuery[R]) *KKQuery[R] {
+	return &KKQuery[R]{
+		iterate: func(r *run) Iterator[R] {
+			iter := q.iterate(r)
+			var current Iterator[R]
+			// Each inner query registers its cleanup in its own scope
+			var inner *run
+			r.onClose(
+				func() {
+					if inner != nil {
+						inner.close()
+					}
+				},
+			)
+			return func() (R, bool) {
+				for {
+					// Return items from current inner query if available
+					if current != nil {
+						item, ok := current()
+						if ok {
+							return item, true
+						}
+						inner.close()
+						current = nil
+						if r.stopped {
+							var zero R
+							return zero, false
+						}
+					}
+
+					// Get next source item
+					item, ok := iter()
+					if !ok {
+						var zero R
+						return zero, false
+					}
+
+					inner = r.scope()
+					current = fn(item).iterate(inner)
+				}
+			}
+		},
+	}
+}
+
+// Scan yields the running accumulator after each item, starting from seed:
+// fn(seed, first), then fn of that and the second item, and so on. The seed
+// itself is not yielded. Each iteration starts again from seed, so the query
+// can be re-iterated safely.
+// This is a function (not a method) because the accumulator can have a different type.
+func Scan[T any, A any](q *KKQuery[T], seed A, fn func(acc A, item T) A) *KKQuery[A] {
+	return &KKQuery[A]{
+		iterate: func(r *run) Iterator[A] {
+			iter := q.iterate(r)
+			acc := seed
+			return func() (A, bool) {
+				item, ok := iter()
+				if !ok {
+					var zero A
+					return zero, false
+				}
+				acc = fn(acc, item)
+				return acc, true
+			}
+		},
+	}
+}
@@ -1,6 +1,7 @@
 package kk
 
 import (
+	"maps"
 	"strconv"
 	"strings"
 	"testing"
@@ -217,3 +218,106 @@ func TestNestedMapped(t *testing.T) {
 		}
 	}
 }
+
+func TestMappedErr(t *testing.T) {
+	input := []string{"1", "2", "3"}
+	q := MappedErr(Query(input), strconv.Atoi)
+	result, err := SliceErr(q)
+
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	expected := []int{1, 2, 3}
+	if len(result) != len(expected) {
+		t.Fatalf("expected length %d, got %d", len(expected), len(result))
+	}
+	for i, v := range result {
+		if v != expected[i] {
+			t.Errorf("at index %d: expected %d, got %d", i, expected[i], v)
+		}
+	}
+}
+
+func TestMappedErrStops(t *testing.T) {
+	input := []string{"1", "x", "3"}
+	var calls int
+	q := MappedErr(
+		Query(input), func(s string) (int, error) {
+			calls++
+			return strconv.Atoi(s)
+		},
+	)
+	result, err := SliceErr(q)
+
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if len(result) != 1 || result[0] != 1 {
+		t.Errorf("expected [1], got %v", result)
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 calls, got %d", calls)
+	}
+}
+
+func TestScan(t *testing.T) {
+	result := Slice(Scan(Query([]int{1, 2, 3, 4}), 0, func(sum int, n int) int { return sum + n }))
+
+	expected := []int{1, 3, 6, 10}
+	if len(result) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, result)
+	}
+	for i, v := range result {
+		if v != expected[i] {
+			t.Errorf("at index %d: expected %d, got %d", i, expected[i], v)
+		}
+	}
+}
+
+func TestScanReiterate(t *testing.T) {
+	q := Scan(Query([]int{5, -2, 3}), 100, func(balance int, n int) int { return balance + n })
+
+	first := Slice(q)
+	second := Slice(q)
+	if len(first) != 3 || first[2] != 106 || second[2] != 106 {
+		t.Errorf("expected [105 103 106] on each iteration, got %v and %v", first, second)
+	}
+}
+
+func TestScanChangesType(t *testing.T) {
+	q := Scan(
+		Query([]string{"a", "b", "a", "c"}), map[string]bool{}, func(seen map[string]bool, s string) map[string]bool {
+			next := maps.Clone(seen)
+			next[s] = true
+			return next
+		},
+	)
+	sizes := Slice(Mapped(q, func(seen map[string]bool) int { return len(seen) }))
+
+	expected := []int{1, 2, 2, 3}
+	if len(sizes) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, sizes)
+	}
+	for i, v := range sizes {
+		if v != expected[i] {
+			t.Errorf("at index %d: expected %d, got %d", i, expected[i], v)
+		}
+	}
+}
+
+func TestScanLazy(t *testing.T) {
+	q := Scan(Generate(func(i int) int { return i + 1 }), 1, func(product int, n int) int { return product * n })
+	result := Slice(q.Take(5))
+
+	if len(result) != 5 || result[4] != 120 {
+		t.Errorf("expected factorials up to 120, got %v", result)
+	}
+}
+
+func TestScanEmpty(t *testing.T) {
+	result := Slice(Scan(Query([]int{}), 7, func(acc int, n int) int { return acc + n }))
+
+	if len(result) != 0 {
+		t.Errorf("expected empty slice, got %v", result)
+	}
+}
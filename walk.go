@@ -0,0 +1,215 @@
+package kk
+
+import (
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// SymlinkPolicy controls how a filesystem walk treats symbolic links.
+type SymlinkPolicy int
+
+const (
+	// SymlinksInclude yields symbolic links without following them. This is the default.
+	SymlinksInclude SymlinkPolicy = iota
+	// SymlinksSkip leaves symbolic links out of the walk.
+	SymlinksSkip
+	// SymlinksFollow yields the target of symbolic links and walks into linked
+	// directories. A link back to a directory being walked is not followed.
+	SymlinksFollow
+)
+
+// WalkOptions configures QueryFS and QueryDir.
+// Patterns use path.Match syntax. A pattern containing a slash is matched
+// against the path relative to the walk root, otherwise against the base name.
+type WalkOptions struct {
+	// Include, if set, only yields entries that match at least one pattern.
+	// Directories are still walked into when they do not match.
+	Include []string
+	// Exclude leaves out entries that match any pattern, and does not walk
+	// into excluded directories.
+	Exclude []string
+	// MaxDepth limits how deep the walk goes. Entries directly under the
+	// root have depth 1. Zero means no limit.
+	MaxDepth int
+	// FilesOnly leaves directories out of the results. They are still walked into.
+	FilesOnly bool
+	// Symlinks controls how symbolic links are treated.
+	Symlinks SymlinkPolicy
+}
+
+// FileEntry is an entry found by a filesystem walk.
+type FileEntry struct {
+	// Path is the slash-separated path of the entry within the filesystem,
+	// or the OS path for QueryDir.
+	Path string
+	// Info describes the entry, or the link target for followed symbolic links.
+	Info fs.FileInfo
+	// Depth is the number of directories below the walk root, starting at 1.
+	Depth int
+}
+
+// QueryFS creates a KKQuery that lazily walks fsys from root in lexical order.
+// Directories are read only when the walk reaches them, so stopping early
+// (Take, First) stops the walk. The root itself is not yielded.
+// Errors reading directories or entries are reported to the terminal operation.
+func QueryFS(fsys fs.FS, root string, opts WalkOptions) *KKQuery[FileEntry] {
+	return &KKQuery[FileEntry]{
+		iterate: func(r *run) Iterator[FileEntry] {
+			w := &walker{fsys: fsys, root: root, opts: opts}
+			if info, err := fs.Stat(fsys, root); err == nil {
+				w.push(root, 0, info)
+			} else {
+				r.fail(err)
+			}
+			return func() (FileEntry, bool) {
+				for !r.done() {
+					entry, ok, err := w.next()
+					if err != nil {
+						if r.fail(err) {
+							continue
+						}
+						return FileEntry{}, false
+					}
+					return entry, ok
+				}
+				return FileEntry{}, false
+			}
+		},
+	}
+}
+
+// QueryDir creates a KKQuery that lazily walks the directory dir on the OS
+// filesystem. Paths are OS paths starting with dir. See QueryFS.
+func QueryDir(dir string, opts WalkOptions) *KKQuery[FileEntry] {
+	return Mapped(
+		QueryFS(os.DirFS(dir), ".", opts), func(e FileEntry) FileEntry {
+			e.Path = filepath.Join(dir, filepath.FromSlash(e.Path))
+			return e
+		},
+	)
+}
+
+// walker holds the state of a depth-first filesystem walk.
+type walker struct {
+	fsys  fs.FS
+	root  string
+	opts  WalkOptions
+	stack []*walkFrame
+}
+
+// walkFrame is a directory whose entries are being walked.
+type walkFrame struct {
+	dir     string
+	depth   int
+	info    fs.FileInfo
+	entries []fs.DirEntry
+	loaded  bool
+	index   int
+}
+
+// push schedules dir to be read once the walk reaches it.
+func (w *walker) push(dir string, depth int, info fs.FileInfo) {
+	w.stack = append(w.stack, &walkFrame{dir: dir, depth: depth, info: info})
+}
+
+// next returns the next entry to yield, or false when the walk is complete.
+func (w *walker) next() (FileEntry, bool, error) {
+	for len(w.stack) > 0 {
+		frame := w.stack[len(w.stack)-1]
+		if !frame.loaded {
+			frame.loaded = true
+			entries, err := fs.ReadDir(w.fsys, frame.dir)
+			frame.entries = entries
+			if err != nil {
+				return FileEntry{}, false, err
+			}
+		}
+		if frame.index >= len(frame.entries) {
+			w.stack = w.stack[:len(w.stack)-1]
+			continue
+		}
+		de := frame.entries[frame.index]
+		frame.index++
+
+		p := path.Join(frame.dir, de.Name())
+		depth := frame.depth + 1
+		if w.excluded(p, de.Name()) {
+			continue
+		}
+
+		isLink := de.Type()&fs.ModeSymlink != 0
+		if isLink && w.opts.Symlinks == SymlinksSkip {
+			continue
+		}
+
+		var info fs.FileInfo
+		var err error
+		if isLink && w.opts.Symlinks == SymlinksFollow {
+			info, err = fs.Stat(w.fsys, p)
+		} else {
+			info, err = de.Info()
+		}
+		if err != nil {
+			return FileEntry{}, false, err
+		}
+
+		if info.IsDir() && (w.opts.MaxDepth == 0 || depth < w.opts.MaxDepth) && !w.onStack(info) {
+			w.push(p, depth, info)
+		}
+		if (info.IsDir() && w.opts.FilesOnly) || !w.included(p, de.Name()) {
+			continue
+		}
+		return FileEntry{Path: p, Info: info, Depth: depth}, true, nil
+	}
+	return FileEntry{}, false, nil
+}
+
+// onStack reports whether info is a directory that is already being walked,
+// which happens when a followed symbolic link points back up the tree.
+func (w *walker) onStack(info fs.FileInfo) bool {
+	for _, frame := range w.stack {
+		if frame.info != nil && os.SameFile(frame.info, info) {
+			return true
+		}
+	}
+	return false
+}
+
+// included reports whether an entry passes the Include patterns.
+func (w *walker) included(p string, name string) bool {
+	if len(w.opts.Include) == 0 {
+		return true
+	}
+	return matchAny(w.opts.Include, w.rel(p), name)
+}
+
+// excluded reports whether an entry matches the Exclude patterns.
+func (w *walker) excluded(p string, name string) bool {
+	return matchAny(w.opts.Exclude, w.rel(p), name)
+}
+
+// rel returns p relative to the walk root.
+func (w *walker) rel(p string) string {
+	if w.root == "." {
+		return p
+	}
+	return strings.TrimPrefix(p, w.root+"/")
+}
+
+// matchAny reports whether any pattern matches rel (patterns with a slash)
+// or name (patterns without one).
+func matchAny(patterns []string, rel string, name string) bool {
+	for _, pattern := range patterns {
+		target := name
+		if strings.Contains(pattern, "/") {
+			target = rel
+		}
+		if ok, _ := path.Match(pattern, target); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,161 @@
+package kk
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+)
+
+func testFS() fstest.MapFS {
+	return fstest.MapFS{
+		"a.txt":           {Data: []byte("a")},
+		"b.jpg":           {Data: []byte("bb")},
+		"docs/c.txt":      {Data: []byte("ccc")},
+		"docs/d.md":       {Data: []byte("dddd")},
+		"docs/deep/e.txt": {Data: []byte("eeeee")},
+		"vendor/f.txt":    {Data: []byte("f")},
+	}
+}
+
+func walkPaths(q *KKQuery[FileEntry]) []string {
+	return Slice(Mapped(q, func(e FileEntry) string { return e.Path }))
+}
+
+func TestQueryFS(t *testing.T) {
+	result := walkPaths(QueryFS(testFS(), ".", WalkOptions{}))
+
+	expected := []string{
+		"a.txt", "b.jpg", "docs", "docs/c.txt", "docs/d.md", "docs/deep", "docs/deep/e.txt",
+		"vendor", "vendor/f.txt",
+	}
+	if !slices.Equal(result, expected) {
+		t.Errorf("expected %v, got %v", expected, result)
+	}
+}
+
+func TestQueryFSEntry(t *testing.T) {
+	entries := Slice(QueryFS(testFS(), ".", WalkOptions{}).Where(func(e FileEntry) bool { return e.Path == "docs/deep/e.txt" }))
+
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(entries))
+	}
+	if entries[0].Depth != 3 {
+		t.Errorf("expected depth 3, got %d", entries[0].Depth)
+	}
+	if entries[0].Info.Size() != 5 || entries[0].Info.Name() != "e.txt" {
+		t.Errorf("unexpected info %v", entries[0].Info)
+	}
+}
+
+func TestQueryFSSubdirectory(t *testing.T) {
+	result := walkPaths(QueryFS(testFS(), "docs", WalkOptions{Include: []string{"deep/*"}}))
+
+	if !slices.Equal(result, []string{"docs/deep/e.txt"}) {
+		t.Errorf("expected [docs/deep/e.txt], got %v", result)
+	}
+}
+
+func TestQueryFSIncludeExclude(t *testing.T) {
+	opts := WalkOptions{Include: []string{"*.txt"}, Exclude: []string{"vendor"}}
+	result := walkPaths(QueryFS(testFS(), ".", opts))
+
+	expected := []string{"a.txt", "docs/c.txt", "docs/deep/e.txt"}
+	if !slices.Equal(result, expected) {
+		t.Errorf("expected %v, got %v", expected, result)
+	}
+}
+
+func TestQueryFSMaxDepthFilesOnly(t *testing.T) {
+	result := walkPaths(QueryFS(testFS(), ".", WalkOptions{MaxDepth: 2, FilesOnly: true}))
+
+	expected := []string{"a.txt", "b.jpg", "docs/c.txt", "docs/d.md", "vendor/f.txt"}
+	if !slices.Equal(result, expected) {
+		t.Errorf("expected %v, got %v", expected, result)
+	}
+}
+
+type countingFS struct {
+	fs.FS
+	reads atomic.Int32
+}
+
+func (c *countingFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	c.reads.Add(1)
+	return fs.ReadDir(c.FS, name)
+}
+
+func TestQueryFSStopsEarly(t *testing.T) {
+	fsys := &countingFS{FS: testFS()}
+	result := walkPaths(QueryFS(fsys, ".", WalkOptions{}).Take(2))
+
+	if len(result) != 2 {
+		t.Errorf("expected length 2, got %d", len(result))
+	}
+	if fsys.reads.Load() != 1 {
+		t.Errorf("expected only the root to be read, got %d reads", fsys.reads.Load())
+	}
+}
+
+func TestQueryFSMissingRoot(t *testing.T) {
+	_, err := SliceErr(QueryFS(testFS(), "missing", WalkOptions{}))
+
+	if err == nil {
+		t.Error("expected error, got nil")
+	}
+}
+
+func TestQueryDirSymlinks(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "sub"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "sub", "file.txt"), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// A link back to the root would loop forever if followed blindly
+	if err := os.Symlink(dir, filepath.Join(dir, "sub", "loop")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	included := walkPaths(QueryDir(dir, WalkOptions{}))
+	if len(included) != 3 || included[1] != filepath.Join(dir, "sub", "file.txt") {
+		t.Errorf("expected sub, file and link, got %v", included)
+	}
+
+	skipped := walkPaths(QueryDir(dir, WalkOptions{Symlinks: SymlinksSkip}))
+	if len(skipped) != 2 {
+		t.Errorf("expected link skipped, got %v", skipped)
+	}
+
+	followed := Slice(QueryDir(dir, WalkOptions{Symlinks: SymlinksFollow}))
+	if len(followed) != 3 {
+		t.Fatalf("expected link followed without looping, got %d entries", len(followed))
+	}
+	if !followed[2].Info.IsDir() {
+		t.Error("expected followed link to report the target directory")
+	}
+}
+
+func TestQueryFSParallel(t *testing.T) {
+	var total atomic.Int64
+	q := QueryFS(testFS(), ".", WalkOptions{FilesOnly: true})
+
+	err := Parallel(
+		context.Background(), q, 3, func(ctx context.Context, e FileEntry) error {
+			total.Add(e.Info.Size())
+			return nil
+		},
+	)
+
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if total.Load() != 16 {
+		t.Errorf("expected total size 16, got %d", total.Load())
+	}
+}
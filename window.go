@@ -0,0 +1,270 @@
+package kk
+
+import (
+	"container/heap"
+	"slices"
+	"time"
+)
+
+// Window yields windows of size consecutive items, starting a new window
+// every step items. A step smaller than size gives overlapping (sliding)
+// windows, equal to size gives Chunk-like tumbling windows, and larger than
+// size gives hopping windows that skip items. Only full windows are yielded.
+// Each window is a new slice. A size or step below one yields nothing.
+// This is a function (not a method) because it returns a different type.
+func Window[T any](q *KKQuery[T], size int, step int) *KKQuery[[]T] {
+	return &KKQuery[[]T]{
+		iterate: func(r *run) Iterator[[]T] {
+			iter := q.iterate(r)
+			done := size < 1 || step < 1
+			var buf []T
+			skip := 0
+			return func() ([]T, bool) {
+				for !done && len(buf) < size {
+					item, ok := iter()
+					if !ok {
+						done = true
+						break
+					}
+					if skip > 0 {
+						skip--
+						continue
+					}
+					buf = append(buf, item)
+				}
+				if done {
+					return nil, false
+				}
+
+				window := slices.Clone(buf)
+				if step >= size {
+					buf = buf[:0]
+					skip = step - size
+				} else {
+					buf = append(buf[:0], buf[step:]...)
+				}
+				return window, true
+			}
+		},
+	}
+}
+
+// TimeWindow is a window of items whose timestamps fall in [Start, End).
+type TimeWindow[T any] struct {
+	Start time.Time
+	End   time.Time
+	Items []T
+}
+
+// TimeWindowOptions configures WindowByTime.
+type TimeWindowOptions struct {
+	// Size is the length of each window.
+	Size time.Duration
+	// Slide is the time between the starts of consecutive windows. Zero or
+	// Size gives tumbling windows; less than Size gives sliding windows in
+	// which an item can belong to several windows.
+	Slide time.Duration
+	// Lateness is how far an item's timestamp may fall behind the latest
+	// timestamp seen and still be added to its windows. A window is yielded
+	// once the latest timestamp minus Lateness reaches its end.
+	Lateness time.Duration
+}
+
+// WindowByTime groups items into time windows by the timestamp ts returns.
+// Windows are aligned to multiples of Slide and yielded in order of start
+// time as soon as they close, so it works on infinite streams whose
+// timestamps are roughly increasing. Items that arrive after all of their
+// windows have closed are dropped. Windows without items are not yielded,
+// and windows still open when the input ends are yielded at the end.
+// A Size of zero or less yields nothing.
+// This is a function (not a method) because it returns a different type.
+func WindowByTime[T any](q *KKQuery[T], ts func(T) time.Time, opts TimeWindowOptions) *KKQuery[TimeWindow[T]] {
+	slide := opts.Slide
+	if slide <= 0 {
+		slide = opts.Size
+	}
+	return &KKQuery[TimeWindow[T]]{
+		iterate: func(r *run) Iterator[TimeWindow[T]] {
+			iter := q.iterate(r)
+			exhausted := opts.Size <= 0
+
+			// Open windows, ordered by start time
+			var open []*TimeWindow[T]
+			var watermark time.Time
+			hasWatermark := false
+
+			add := func(item T) {
+				at := ts(item)
+				if next := at.Add(-opts.Lateness); !hasWatermark || next.After(watermark) {
+					watermark, hasWatermark = next, true
+				}
+				for start := at.Truncate(slide); start.Add(opts.Size).After(at); start = start.Add(-slide) {
+					end := start.Add(opts.Size)
+					if !end.After(watermark) {
+						// Late: this window has already been yielded
+						continue
+					}
+					i, found := slices.BinarySearchFunc(
+						open, start, func(w *TimeWindow[T], start time.Time) int {
+							return w.Start.Compare(start)
+						},
+					)
+					if !found {
+						open = slices.Insert(open, i, &TimeWindow[T]{Start: start, End: end})
+					}
+					open[i].Items = append(open[i].Items, item)
+				}
+			}
+
+			return func() (TimeWindow[T], bool) {
+				for !exhausted && (len(open) == 0 || open[0].End.After(watermark)) {
+					item, ok := iter()
+					if !ok {
+						exhausted = true
+						break
+					}
+					add(item)
+				}
+				// Open windows are incomplete if the run stopped
+				if len(open) == 0 || r.stopped {
+					return TimeWindow[T]{}, false
+				}
+				window := open[0]
+				open = open[1:]
+				return *window, true
+			}
+		},
+	}
+}
+
+// Session is a group of items with the same key whose timestamps are no more
+// than the session gap apart. Start and End are the earliest and latest
+// timestamps in the session.
+type Session[K comparable, T any] struct {
+	Key   K
+	Start time.Time
+	End   time.Time
+	Items []T
+}
+
+// SessionWindow groups items per key into sessions that stay open until no
+// item for the key arrives for longer than gap. Inactivity is measured
+// against the latest timestamp seen across all keys, so a session is yielded
+// as soon as the stream moves more than gap past its End; this works on
+// infinite streams. Sessions still open when the input ends are yielded at
+// the end. Sessions are yielded in order of End, and items keep their query
+// order within a session.
+// This is a function (not a method) because it returns a different type.
+func SessionWindow[T any, K comparable](
+	q *KKQuery[T], keyFn func(T) K, ts func(T) time.Time, gap time.Duration,
+) *KKQuery[Session[K, T]] {
+	return &KKQuery[Session[K, T]]{
+		iterate: func(r *run) Iterator[Session[K, T]] {
+			iter := q.iterate(r)
+			open := make(map[K]*openSession[K, T])
+			// Open sessions ordered by End, so only expired ones are visited
+			var byEnd sessionHeap[K, T]
+			seq := 0
+			var ready []Session[K, T]
+			var latest time.Time
+			hasLatest := false
+			exhausted := false
+
+			// closeUntil moves open sessions to ready in order of End while expired returns true
+			closeUntil := func(expired func(s *openSession[K, T]) bool) {
+				for len(byEnd) > 0 && expired(byEnd[0]) {
+					s := heap.Pop(&byEnd).(*openSession[K, T])
+					delete(open, s.Key)
+					ready = append(ready, s.Session)
+				}
+			}
+
+			add := func(item T) {
+				at := ts(item)
+				if !hasLatest || at.After(latest) {
+					latest, hasLatest = at, true
+					closeUntil(func(s *openSession[K, T]) bool { return latest.Sub(s.End) > gap })
+				}
+				key := keyFn(item)
+				s := open[key]
+				if s == nil {
+					s = &openSession[K, T]{Session: Session[K, T]{Key: key, Start: at, End: at}, seq: seq}
+					seq++
+					open[key] = s
+					heap.Push(&byEnd, s)
+				}
+				if at.Before(s.Start) {
+					s.Start = at
+				}
+				if at.After(s.End) {
+					s.End = at
+					heap.Fix(&byEnd, s.index)
+				}
+				s.Items = append(s.Items, item)
+			}
+
+			return func() (Session[K, T], bool) {
+				for len(ready) == 0 && !exhausted {
+					item, ok := iter()
+					if !ok {
+						exhausted = true
+						// Open sessions are incomplete if the run stopped
+						if !r.stopped {
+							closeUntil(func(*openSession[K, T]) bool { return true })
+						}
+						break
+					}
+					add(item)
+				}
+				if len(ready) == 0 {
+					return Session[K, T]{}, false
+				}
+				s := ready[0]
+				ready = ready[1:]
+				return s, true
+			}
+		},
+	}
+}
+
+// openSession is a session that can still receive items.
+type openSession[K comparable, T any] struct {
+	Session[K, T]
+	seq   int // order in which sessions were opened, to break ties
+	index int // position in the sessionHeap
+}
+
+// sessionHeap is a min-heap of open sessions ordered by End, then by when
+// they were opened. It implements heap.Interface.
+type sessionHeap[K comparable, T any] []*openSession[K, T]
+
+func (h sessionHeap[K, T]) Len() int {
+	return len(h)
+}
+
+func (h sessionHeap[K, T]) Less(i, j int) bool {
+	if c := h[i].End.Compare(h[j].End); c != 0 {
+		return c < 0
+	}
+	return h[i].seq < h[j].seq
+}
+
+func (h sessionHeap[K, T]) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *sessionHeap[K, T]) Push(x any) {
+	s := x.(*openSession[K, T])
+	s.index = len(*h)
+	*h = append(*h, s)
+}
+
+func (h *sessionHeap[K, T]) Pop() any {
+	old := *h
+	s := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	return s
+}
@@ -0,0 +1,295 @@
+package kk
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestWindowSliding(t *testing.T) {
+	result := Slice(Window(Range(1, 6, 1), 3, 1))
+
+	expected := "[[1 2 3] [2 3 4] [3 4 5]]"
+	if fmt.Sprint(result) != expected {
+		t.Errorf("expected %s, got %v", expected, result)
+	}
+}
+
+func TestWindowTumbling(t *testing.T) {
+	result := Slice(Window(Range(1, 8, 1), 3, 3))
+
+	// Unlike Chunk, the partial window [7] is not yielded
+	expected := "[[1 2 3] [4 5 6]]"
+	if fmt.Sprint(result) != expected {
+		t.Errorf("expected %s, got %v", expected, result)
+	}
+}
+
+func TestWindowHopping(t *testing.T) {
+	result := Slice(Window(Range(1, 10, 1), 2, 4))
+
+	expected := "[[1 2] [5 6]]"
+	if fmt.Sprint(result) != expected {
+		t.Errorf("expected %s, got %v", expected, result)
+	}
+}
+
+func TestWindowIndependentSlices(t *testing.T) {
+	result := Slice(Window(Range(1, 5, 1), 2, 1))
+	result[0][1] = 99
+
+	if result[1][0] != 2 {
+		t.Errorf("expected windows not to share memory, got %v", result)
+	}
+}
+
+func TestWindowShortInput(t *testing.T) {
+	result := Slice(Window(Query([]int{1, 2}), 3, 1))
+
+	if len(result) != 0 {
+		t.Errorf("expected no windows, got %v", result)
+	}
+}
+
+func TestWindowInvalid(t *testing.T) {
+	if n := Count(Window(Query([]int{1, 2}), 0, 1)); n != 0 {
+		t.Errorf("expected no windows for size 0, got %d", n)
+	}
+	if n := Count(Window(Query([]int{1, 2}), 1, 0)); n != 0 {
+		t.Errorf("expected no windows for step 0, got %d", n)
+	}
+}
+
+func TestWindowMovingAverage(t *testing.T) {
+	averages := Mapped(
+		Window(Query([]float64{2, 4, 6, 8}), 2, 1), func(w []float64) float64 {
+			avg, _ := Average(Query(w), func(n float64) float64 { return n })
+			return avg
+		},
+	)
+	result := Slice(averages)
+
+	expected := "[3 5 7]"
+	if fmt.Sprint(result) != expected {
+		t.Errorf("expected %s, got %v", expected, result)
+	}
+}
+
+type event struct {
+	At   time.Time
+	Name string
+}
+
+var windowBase = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func at(seconds float64, name string) event {
+	return event{At: windowBase.Add(time.Duration(seconds * float64(time.Second))), Name: name}
+}
+
+func eventTime(e event) time.Time { return e.At }
+
+func describeWindows(windows []TimeWindow[event]) string {
+	var s string
+	for _, w := range windows {
+		s += fmt.Sprintf("[%d-%d", w.Start.Sub(windowBase)/time.Second, w.End.Sub(windowBase)/time.Second)
+		for _, e := range w.Items {
+			s += " " + e.Name
+		}
+		s += "]"
+	}
+	return s
+}
+
+func TestWindowByTimeTumbling(t *testing.T) {
+	events := []event{at(0, "a"), at(4, "b"), at(5, "c"), at(12, "d")}
+	result := Slice(WindowByTime(Query(events), eventTime, TimeWindowOptions{Size: 5 * time.Second}))
+
+	// The empty window [5-10) is not yielded
+	expected := "[0-5 a b][5-10 c][10-15 d]"
+	if got := describeWindows(result); got != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+}
+
+func TestWindowByTimeSliding(t *testing.T) {
+	events := []event{at(1, "a"), at(3, "b"), at(5, "c")}
+	opts := TimeWindowOptions{Size: 4 * time.Second, Slide: 2 * time.Second}
+	result := Slice(WindowByTime(Query(events), eventTime, opts))
+
+	expected := "[-2-2 a][0-4 a b][2-6 b c][4-8 c]"
+	if got := describeWindows(result); got != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+}
+
+func TestWindowByTimeLateness(t *testing.T) {
+	// d is 4s behind the latest event, c arrives after its window closed
+	events := []event{at(1, "a"), at(6, "b"), at(2, "c"), at(13, "x"), at(9, "d")}
+
+	strict := Slice(WindowByTime(Query(events), eventTime, TimeWindowOptions{Size: 5 * time.Second}))
+	if got, expected := describeWindows(strict), "[0-5 a][5-10 b][10-15 x]"; got != expected {
+		t.Errorf("without lateness: expected %s, got %s", expected, got)
+	}
+
+	opts := TimeWindowOptions{Size: 5 * time.Second, Lateness: 5 * time.Second}
+	lenient := Slice(WindowByTime(Query(events), eventTime, opts))
+	if got, expected := describeWindows(lenient), "[0-5 a c][5-10 b d][10-15 x]"; got != expected {
+		t.Errorf("with lateness: expected %s, got %s", expected, got)
+	}
+}
+
+func TestWindowByTimeStreams(t *testing.T) {
+	// An infinite stream of events one second apart
+	q := Mapped(
+		Generate(func(i int) int { return i }), func(i int) event {
+			return at(float64(i), fmt.Sprint(i))
+		},
+	)
+	result := Slice(WindowByTime(q, eventTime, TimeWindowOptions{Size: 3 * time.Second}).Take(2))
+
+	expected := "[0-3 0 1 2][3-6 3 4 5]"
+	if got := describeWindows(result); got != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+}
+
+func TestWindowByTimeError(t *testing.T) {
+	q := MappedErr(
+		Query([]int{0, 1, 6, 7}), func(n int) (event, error) {
+			if n == 7 {
+				return event{}, fmt.Errorf("bad item %d", n)
+			}
+			return at(float64(n), fmt.Sprint(n)), nil
+		},
+	)
+	result, err := SliceErr(WindowByTime(q, eventTime, TimeWindowOptions{Size: 5 * time.Second}))
+
+	if err == nil || err.Error() != "bad item 7" {
+		t.Errorf("expected bad item 7, got %v", err)
+	}
+	// The open window [5-10) is incomplete and not yielded
+	if got, expected := describeWindows(result), "[0-5 0 1]"; got != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+}
+
+type click struct {
+	User string
+	At   time.Time
+}
+
+func clickAt(user string, seconds int) click {
+	return click{User: user, At: windowBase.Add(time.Duration(seconds) * time.Second)}
+}
+
+func describeSessions(sessions []Session[string, click]) string {
+	var s string
+	for _, session := range sessions {
+		s += fmt.Sprintf(
+			"[%s %d-%d n=%d]", session.Key,
+			session.Start.Sub(windowBase)/time.Second, session.End.Sub(windowBase)/time.Second, len(session.Items),
+		)
+	}
+	return s
+}
+
+func clickUser(c click) string    { return c.User }
+func clickTime(c click) time.Time { return c.At }
+
+func TestSessionWindow(t *testing.T) {
+	clicks := []click{
+		clickAt("ann", 0), clickAt("bob", 1), clickAt("ann", 5), clickAt("bob", 20),
+		clickAt("ann", 21), clickAt("ann", 30),
+	}
+	result := Slice(SessionWindow(Query(clicks), clickUser, clickTime, 10*time.Second))
+
+	// Both first sessions expire at 20, in order of End; the rest flush at the end
+	expected := "[bob 1-1 n=1][ann 0-5 n=2][bob 20-20 n=1][ann 21-30 n=2]"
+	if got := describeSessions(result); got != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+}
+
+func TestSessionWindowGapBoundary(t *testing.T) {
+	// An item exactly gap after the last one continues the session
+	clicks := []click{clickAt("ann", 0), clickAt("ann", 10), clickAt("ann", 21)}
+	result := Slice(SessionWindow(Query(clicks), clickUser, clickTime, 10*time.Second))
+
+	expected := "[ann 0-10 n=2][ann 21-21 n=1]"
+	if got := describeSessions(result); got != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+}
+
+func TestSessionWindowOutOfOrder(t *testing.T) {
+	clicks := []click{clickAt("ann", 10), clickAt("ann", 4), clickAt("ann", 12)}
+	result := Slice(SessionWindow(Query(clicks), clickUser, clickTime, 10*time.Second))
+
+	expected := "[ann 4-12 n=3]"
+	if got := describeSessions(result); got != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+}
+
+func TestSessionWindowStreams(t *testing.T) {
+	// Bursts of three clicks every minute, forever
+	q := Mapped(
+		Generate(func(i int) int { return i }), func(i int) click {
+			return clickAt("ann", i/3*60+i%3)
+		},
+	)
+	result := Slice(SessionWindow(q, clickUser, clickTime, 30*time.Second).Take(2))
+
+	expected := "[ann 0-2 n=3][ann 60-62 n=3]"
+	if got := describeSessions(result); got != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+}
+
+func TestSessionWindowError(t *testing.T) {
+	q := MappedErr(
+		Query([]int{0, 1, 50, 51}), func(n int) (click, error) {
+			if n == 51 {
+				return click{}, fmt.Errorf("bad item %d", n)
+			}
+			return clickAt("ann", n), nil
+		},
+	)
+	result, err := SliceErr(SessionWindow(q, clickUser, clickTime, 10*time.Second))
+
+	if err == nil || err.Error() != "bad item 51" {
+		t.Errorf("expected bad item 51, got %v", err)
+	}
+	// The session still open at the error is incomplete and not yielded
+	if got, expected := describeSessions(result), "[ann 0-1 n=2]"; got != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+}
+
+func TestSessionWindowManyKeys(t *testing.T) {
+	// 100 users click in turn, one click per second, in two bursts 1000s apart
+	var clicks []click
+	for i := 0; i < 2000; i++ {
+		at := i
+		if i >= 1000 {
+			at += 1000
+		}
+		clicks = append(clicks, clickAt(fmt.Sprint("u", i%100), at))
+	}
+	result := Slice(SessionWindow(Query(clicks), clickUser, clickTime, 150*time.Second))
+
+	if len(result) != 200 {
+		t.Fatalf("expected 200 sessions, got %d", len(result))
+	}
+	for i, s := range result {
+		if len(s.Items) != 10 {
+			t.Errorf("session %d: expected 10 items, got %d", i, len(s.Items))
+		}
+		if i > 0 && s.End.Before(result[i-1].End) {
+			t.Errorf("session %d: expected sessions in order of End", i)
+		}
+	}
+	if result[0].Key != "u0" || result[100].Key != "u0" || result[100].Start.Sub(windowBase) != 2000*time.Second {
+		t.Errorf("expected the second burst to start new sessions, got %v and %v", result[0], result[100])
+	}
+}
@@ -0,0 +1,89 @@
+package kk
+
+// Pair holds one item from each of two zipped queries.
+type Pair[A any, B any] struct {
+	First  A
+	Second B
+}
+
+// LongestPair holds one position of two queries zipped by ZipLongest.
+// When one query is longer, the missing side holds its zero value and its
+// Has flag is false.
+type LongestPair[A any, B any] struct {
+	First     A
+	Second    B
+	HasFirst  bool
+	HasSecond bool
+}
+
+// Zip pairs the items of two queries by position, stopping at the end of the
+// shorter one. Neither query is materialized.
+// This is a function (not a method) because it returns a different type.
+func Zip[A any, B any](a *KKQuery[A], b *KKQuery[B]) *KKQuery[Pair[A, B]] {
+	return ZipWith(a, b, func(first A, second B) Pair[A, B] {
+		return Pair[A, B]{First: first, Second: second}
+	})
+}
+
+// ZipWith combines the items of two queries by position with fn, stopping at
+// the end of the shorter one.
+// This is a function (not a method) because it returns a different type.
+func ZipWith[A any, B any, R any](a *KKQuery[A], b *KKQuery[B], fn func(A, B) R) *KKQuery[R] {
+	return &KKQuery[R]{
+		iterate: func(r *run) Iterator[R] {
+			iterA := a.iterate(r)
+			iterB := b.iterate(r)
+			done := false
+			return func() (R, bool) {
+				var zero R
+				if done {
+					return zero, false
+				}
+				first, ok := iterA()
+				if !ok {
+					done = true
+					return zero, false
+				}
+				second, ok := iterB()
+				if !ok {
+					done = true
+					return zero, false
+				}
+				return fn(first, second), true
+			}
+		},
+	}
+}
+
+// ZipLongest pairs the items of two queries by position until both are
+// exhausted. Once the shorter query ends, its side is zero-filled and marked
+// missing in the LongestPair.
+// This is a function (not a method) because it returns a different type.
+func ZipLongest[A any, B any](a *KKQuery[A], b *KKQuery[B]) *KKQuery[LongestPair[A, B]] {
+	return &KKQuery[LongestPair[A, B]]{
+		iterate: func(r *run) Iterator[LongestPair[A, B]] {
+			iterA := a.iterate(r)
+			iterB := b.iterate(r)
+			doneA, doneB := false, false
+			return func() (LongestPair[A, B], bool) {
+				var pair LongestPair[A, B]
+				if !doneA {
+					pair.First, pair.HasFirst = iterA()
+					doneA = !pair.HasFirst
+				}
+				// An input that ended because the run stopped is not exhausted
+				if r.stopped {
+					return LongestPair[A, B]{}, false
+				}
+				if !doneB {
+					pair.Second, pair.HasSecond = iterB()
+					doneB = !pair.HasSecond
+				}
+				if r.stopped || (doneA && doneB) {
+					return LongestPair[A, B]{}, false
+				}
+				return pair, true
+			}
+		},
+	}
+}
@@ -0,0 +1,110 @@
+package kk
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestZip(t *testing.T) {
+	ids := Query([]int{1, 2, 3})
+	names := Query([]string{"a", "b"})
+	result := Slice(Zip(ids, names))
+
+	expected := []Pair[int, string]{{1, "a"}, {2, "b"}}
+	if len(result) != len(expected) {
+		t.Fatalf("expected length %d, got %d", len(expected), len(result))
+	}
+	for i, v := range result {
+		if v != expected[i] {
+			t.Errorf("at index %d: expected %v, got %v", i, expected[i], v)
+		}
+	}
+}
+
+func TestZipInfinite(t *testing.T) {
+	indexed := Zip(Generate(func(i int) int { return i }), Query([]string{"x", "y"}))
+	result := Slice(indexed)
+
+	if len(result) != 2 || result[1].First != 1 || result[1].Second != "y" {
+		t.Errorf("expected [{0 x} {1 y}], got %v", result)
+	}
+}
+
+func TestZipWith(t *testing.T) {
+	q := ZipWith(
+		Query([]string{"a", "b", "c"}), Range(1, 10, 1), func(s string, n int) string {
+			return fmt.Sprintf("%s%d", s, n)
+		},
+	)
+	result := Slice(q)
+
+	expected := []string{"a1", "b2", "c3"}
+	if len(result) != len(expected) {
+		t.Fatalf("expected length %d, got %d", len(expected), len(result))
+	}
+	for i, v := range result {
+		if v != expected[i] {
+			t.Errorf("at index %d: expected %s, got %s", i, expected[i], v)
+		}
+	}
+}
+
+func TestZipReiterate(t *testing.T) {
+	q := Zip(Query([]int{1, 2}), Query([]int{3, 4}))
+
+	if Count(q) != 2 || Count(q) != 2 {
+		t.Error("expected 2 pairs on each iteration")
+	}
+}
+
+func TestZipError(t *testing.T) {
+	q := Zip(MappedErr(Query([]int{1, 2, 3}), failOn(2)), Query([]int{4, 5, 6}))
+	result, err := SliceErr(q)
+
+	if len(result) != 1 {
+		t.Errorf("expected length 1, got %d", len(result))
+	}
+	if err == nil || err.Error() != "bad item 2" {
+		t.Errorf("expected bad item 2, got %v", err)
+	}
+}
+
+func TestZipLongest(t *testing.T) {
+	result := Slice(ZipLongest(Query([]int{1}), Query([]string{"a", "b"})))
+
+	expected := []LongestPair[int, string]{
+		{First: 1, Second: "a", HasFirst: true, HasSecond: true},
+		{First: 0, Second: "b", HasFirst: false, HasSecond: true},
+	}
+	if len(result) != len(expected) {
+		t.Fatalf("expected length %d, got %d", len(expected), len(result))
+	}
+	for i, v := range result {
+		if v != expected[i] {
+			t.Errorf("at index %d: expected %v, got %v", i, expected[i], v)
+		}
+	}
+}
+
+func TestZipLongestSecondShorter(t *testing.T) {
+	result := Slice(ZipLongest(Query([]int{1, 2, 3}), Query([]int{4})))
+
+	if len(result) != 3 {
+		t.Fatalf("expected length 3, got %d", len(result))
+	}
+	if result[2].First != 3 || result[2].HasSecond || result[2].Second != 0 {
+		t.Errorf("expected {3 0 true false}, got %v", result[2])
+	}
+}
+
+func TestZipLongestStopsOnError(t *testing.T) {
+	q := ZipLongest(MappedErr(Query([]int{1, 2}), failOn(2)), Query([]int{3, 4, 5}))
+	result, err := SliceErr(q)
+
+	if len(result) != 1 {
+		t.Errorf("expected length 1, got %d", len(result))
+	}
+	if err == nil {
+		t.Error("expected error, got nil")
+	}
+}